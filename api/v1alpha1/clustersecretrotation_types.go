@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterSecretRotationSpec defines desired state
+// +kubebuilder:validation:XValidation:rule="has(self.vaultPath) || has(self.engine) || has(self.sources)",message="either vaultPath, engine or sources must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.sources) || !(has(self.vaultPath) || has(self.engine))",message="sources cannot be combined with vaultPath or engine"
+// +kubebuilder:validation:XValidation:rule="!has(self.sources) || !(has(self.keyMapping) || has(self.encoding))",message="keyMapping and encoding are set per source when sources are used"
+// +kubebuilder:validation:XValidation:rule="!has(self.engine) || self.engine in ['kv-v1', 'kv-v2']",message="only the kv-v1 and kv-v2 engines can be synced to several namespaces"
+// +kubebuilder:validation:XValidation:rule="!has(self.engine) || (has(self.mount) && has(self.path))",message="kv engines require mount and path"
+// +kubebuilder:validation:XValidation:rule="!has(self.version) || (has(self.engine) && self.engine == 'kv-v2')",message="version can only be pinned for the kv-v2 engine"
+type ClusterSecretRotationSpec struct {
+	SecretDataSpec `json:",inline"`
+	// TargetSecret is the name of the secret created in every selected namespace
+	TargetSecret string `json:"targetSecret"`
+	// NamespaceSelector selects the namespaces the secret is copied to; an
+	// empty selector selects every namespace
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	// RefreshInterval is how often the secret is synced from Vault (defaults to 10m)
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+	// RetryInterval is how soon a failed sync is retried (defaults to 1m)
+	RetryInterval *metav1.Duration `json:"retryInterval,omitempty"`
+}
+
+// ClusterSecretRotationStatus defines observed state
+type ClusterSecretRotationStatus struct {
+	// ObservedGeneration is the generation of the spec the status was computed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastSyncTime is when the secret was last read from Vault and copied to all selected namespaces
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// LastChangeTime is when the data of the secret last changed
+	LastChangeTime *metav1.Time `json:"lastChangeTime,omitempty"`
+	// SecretChecksum is the checksum of the current secret data
+	SecretChecksum string `json:"secretChecksum,omitempty"`
+	// SyncedVersion describes the KV v2 secret version that was last synced
+	SyncedVersion *VaultSecretVersion `json:"syncedVersion,omitempty"`
+	// Sources reports what was synced from each of the sources, in spec order
+	Sources []SourceStatus `json:"sources,omitempty"`
+	// SyncedNamespaces is the number of selected namespaces holding the current secret
+	SyncedNamespaces int32 `json:"syncedNamespaces,omitempty"`
+	// Namespaces reports the sync state of the secret in each selected namespace
+	// +listType=map
+	// +listMapKey=namespace
+	Namespaces []NamespaceSyncStatus `json:"namespaces,omitempty"`
+	// NextScheduledSync is when the secret will next be synced from Vault
+	NextScheduledSync *metav1.Time `json:"nextScheduledSync,omitempty"`
+	// Conditions represent the latest observations of the ClusterSecretRotation's state
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NamespaceSyncStatus is the sync state of a ClusterSecretRotation in one namespace
+type NamespaceSyncStatus struct {
+	// Namespace is the selected namespace
+	Namespace string `json:"namespace"`
+	// Synced is true when the secret in the namespace holds the current data
+	Synced bool `json:"synced"`
+	// Reason is why the secret is not synced, in CamelCase
+	Reason string `json:"reason,omitempty"`
+	// Message explains why the secret is not synced
+	Message string `json:"message,omitempty"`
+	// LastSyncTime is when the secret in the namespace was last synced
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+const (
+	// ClusterManagedByLabel is set on the copies of a ClusterSecretRotation's
+	// secret to the name of the ClusterSecretRotation
+	ClusterManagedByLabel = "secrets.github.com/clustersecretrotation"
+	// ReasonNamespaceSyncFailed means the secret could not be synced to some selected namespaces
+	ReasonNamespaceSyncFailed = "NamespaceSyncFailed"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.targetSecret`
+//+kubebuilder:printcolumn:name="Namespaces",type=integer,JSONPath=`.status.syncedNamespaces`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterSecretRotation syncs a Vault secret into a Secret in every namespace
+// matching a label selector
+type ClusterSecretRotation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSecretRotationSpec   `json:"spec,omitempty"`
+	Status ClusterSecretRotationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterSecretRotationList contains a list of ClusterSecretRotation
+type ClusterSecretRotationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSecretRotation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterSecretRotation{}, &ClusterSecretRotationList{})
+}
@@ -25,35 +25,606 @@ import (
 
 // WorkloadReference defines a workload that should be updated when secrets change
 type WorkloadReference struct {
+	// APIVersion is the API version of the workload, e.g. argoproj.io/v1alpha1
+	// (optional for Deployment, StatefulSet, DaemonSet, ReplicaSet and CronJob)
+	APIVersion string `json:"apiVersion,omitempty"`
 	// Kind is the workload kind (e.g., Deployment, StatefulSet, DaemonSet)
 	Kind string `json:"kind"`
 	// Name is the name of the workload
 	Name string `json:"name"`
 	// Namespace is the namespace of the workload (optional, defaults to SecretRotation namespace)
 	Namespace string `json:"namespace,omitempty"`
+	// PodTemplatePath is the dot separated path of the pod template in the
+	// workload (defaults to spec.jobTemplate.spec.template for CronJobs and
+	// spec.template for everything else)
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_]+(\.[A-Za-z0-9_]+)*$`
+	PodTemplatePath string `json:"podTemplatePath,omitempty"`
+}
+
+// WorkloadKind is a workload kind that can be selected or discovered
+// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet;ReplicaSet;CronJob
+type WorkloadKind string
+
+// WorkloadSelector selects target workloads by their labels
+type WorkloadSelector struct {
+	// LabelSelector selects workloads by their labels; an empty selector
+	// selects every workload
+	LabelSelector metav1.LabelSelector `json:"labelSelector"`
+	// NamespaceSelector selects the namespaces workloads are selected in
+	// (optional, defaults to the SecretRotation namespace). Other namespaces
+	// can only be selected when the manager runs with --cross-namespace-workloads.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Kinds restricts the selected workload kinds (defaults to every kind)
+	Kinds []WorkloadKind `json:"kinds,omitempty"`
+}
+
+// SecretEngine is the Vault secrets engine a secret is read from
+// +kubebuilder:validation:Enum=kv-v1;kv-v2;database;pki
+type SecretEngine string
+
+const (
+	// SecretEngineKVv1 is the version 1 key/value secrets engine
+	SecretEngineKVv1 SecretEngine = "kv-v1"
+	// SecretEngineKVv2 is the versioned key/value secrets engine
+	SecretEngineKVv2 SecretEngine = "kv-v2"
+	// SecretEngineDatabase issues dynamic, leased database credentials
+	SecretEngineDatabase SecretEngine = "database"
+	// SecretEnginePKI issues TLS certificates written as kubernetes.io/tls secrets
+	SecretEnginePKI SecretEngine = "pki"
+)
+
+// VaultSource identifies a secret in Vault
+type VaultSource struct {
+	// VaultPath is the raw API path of the secret; KV v2 paths must include "/data/".
+	// Prefer engine, mount and path, which do not rely on guessing the engine.
+	VaultPath string `json:"vaultPath,omitempty"`
+	// Engine is the secrets engine serving the secret
+	Engine SecretEngine `json:"engine,omitempty"`
+	// Mount is the path the secrets engine is mounted at (e.g., "secret";
+	// defaults to "database" for the database engine and "pki" for the pki engine)
+	Mount string `json:"mount,omitempty"`
+	// Path is the path of the secret within the mount (e.g., "myapp/database")
+	Path string `json:"path,omitempty"`
+	// Version pins a kv-v2 secret to a specific version (defaults to the latest version)
+	// +kubebuilder:validation:Minimum=1
+	Version *int `json:"version,omitempty"`
+	// Role is the database role credentials are requested for, or the PKI role
+	// certificates are issued by (database and pki engines only)
+	Role string `json:"role,omitempty"`
+	// RenewBefore is how long before the lease expires it is renewed or, once Vault
+	// no longer extends it, new credentials are requested and workloads restarted
+	// (database engine only, defaults to a third of the lease duration, which is
+	// also used when renewBefore is not shorter than the lease duration)
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+	// PKI describes the certificate to issue (pki engine only)
+	PKI *PKICertificate `json:"pki,omitempty"`
+}
+
+// PKICertificate describes a certificate issued by the Vault PKI engine
+type PKICertificate struct {
+	// CommonName is the requested common name of the certificate
+	// +kubebuilder:validation:MinLength=1
+	CommonName string `json:"commonName"`
+	// AltNames are additional DNS names or email addresses to include
+	AltNames []string `json:"altNames,omitempty"`
+	// IPSANs are IP addresses to include as subject alternative names
+	IPSANs []string `json:"ipSans,omitempty"`
+	// TTL is the requested lifetime of the certificate (defaults to the role's TTL)
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+	// RenewAtPercent is the percentage of the certificate's lifetime after which
+	// a new certificate is issued (defaults to 67)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	RenewAtPercent *int32 `json:"renewAtPercent,omitempty"`
+}
+
+// SecretDataSpec describes the Vault data written to a target secret and how
+// it is transformed on the way
+type SecretDataSpec struct {
+	VaultSource `json:",inline"`
+	// Sources are several KV secrets merged into the target secret, instead of
+	// the single source above (optional)
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	Sources []SecretSource `json:"sources,omitempty"`
+	// ConflictPolicy decides what happens when several sources write the same
+	// secret key (defaults to Error)
+	// +kubebuilder:default=Error
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"`
+	// KeyMapping selects and renames the Vault keys written to the target secret
+	// (optional, defaults to copying every key as is)
+	KeyMapping *KeyMapping `json:"keyMapping,omitempty"`
+	// Encoding configures how Vault values are written to the target secret (optional)
+	Encoding *ValueEncoding `json:"encoding,omitempty"`
+	// Template renders secret keys from the Vault data with Go templates (optional)
+	Template *SecretTemplate `json:"template,omitempty"`
 }
 
 // SecretRotationSpec defines desired state
+// +kubebuilder:validation:XValidation:rule="has(self.vaultPath) || has(self.engine) || has(self.sources)",message="either vaultPath, engine or sources must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.sources) || !(has(self.vaultPath) || has(self.engine))",message="sources cannot be combined with vaultPath or engine"
+// +kubebuilder:validation:XValidation:rule="!has(self.sources) || !(has(self.keyMapping) || has(self.encoding))",message="keyMapping and encoding are set per source when sources are used"
+// +kubebuilder:validation:XValidation:rule="!has(self.engine) || !(self.engine in ['kv-v1', 'kv-v2']) || (has(self.mount) && has(self.path))",message="kv engines require mount and path"
+// +kubebuilder:validation:XValidation:rule="!has(self.engine) || self.engine != 'database' || has(self.role)",message="the database engine requires role"
+// +kubebuilder:validation:XValidation:rule="!has(self.engine) || self.engine != 'pki' || (has(self.role) && has(self.pki))",message="the pki engine requires role and pki"
+// +kubebuilder:validation:XValidation:rule="!has(self.pki) || (has(self.engine) && self.engine == 'pki')",message="pki can only be set for the pki engine"
+// +kubebuilder:validation:XValidation:rule="!has(self.version) || (has(self.engine) && self.engine == 'kv-v2')",message="version can only be pinned for the kv-v2 engine"
+// +kubebuilder:validation:XValidation:rule="!has(self.rollback) || !has(self.engine) || self.engine in ['kv-v1', 'kv-v2']",message="rollback is only supported for the kv engines"
+// +kubebuilder:validation:XValidation:rule="!has(self.rotation) || (has(self.engine) && self.engine == 'kv-v2' && !has(self.version))",message="rotation requires the kv-v2 engine without a pinned version"
+// +kubebuilder:validation:XValidation:rule="!has(self.keyMapping) || !has(self.engine) || self.engine != 'pki'",message="keyMapping cannot be used with the pki engine"
+// +kubebuilder:validation:XValidation:rule="!has(self.template) || !has(self.engine) || self.engine != 'pki' || self.template.merge",message="templates for the pki engine must set merge to keep the tls keys"
 type SecretRotationSpec struct {
-	VaultPath    string `json:"vaultPath"`
-	TargetSecret string `json:"targetSecret"`
+	SecretDataSpec `json:",inline"`
+	TargetSecret   string `json:"targetSecret"`
 	// TargetWorkloads are the workloads that should be updated when the secret changes
 	TargetWorkloads []WorkloadReference `json:"targetWorkloads,omitempty"`
+	// WorkloadSelector also targets the workloads matching a label selector (optional)
+	WorkloadSelector *WorkloadSelector `json:"workloadSelector,omitempty"`
+	// DiscoverWorkloads also targets the workloads in the namespace whose pod
+	// template uses the target secret in env, envFrom or a volume
+	DiscoverWorkloads bool `json:"discoverWorkloads,omitempty"`
 	// AnnotationPrefix is the prefix for the checksum annotation (defaults to "secrets.github.com/")
 	AnnotationPrefix string `json:"annotationPrefix,omitempty"`
+	// VaultAuthRef is the name of a VaultAuth in the same namespace used to log in to Vault
+	// (optional, defaults to the operator's own Vault client)
+	VaultAuthRef string `json:"vaultAuthRef,omitempty"`
+	// Rotation makes the operator generate new values for keys of the kv-v2
+	// secret and write them back to Vault (optional)
+	Rotation *RotationPolicy `json:"rotation,omitempty"`
+	// RefreshInterval is how often the secret is synced from Vault (defaults to 10m)
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+	// RetryInterval is how soon a failed sync is retried (defaults to 1m)
+	RetryInterval *metav1.Duration `json:"retryInterval,omitempty"`
+	// TimeZone is the IANA time zone rotation schedules and maintenance windows
+	// are evaluated in (defaults to UTC)
+	TimeZone string `json:"timeZone,omitempty"`
+	// MaintenanceWindows restrict when target workloads are restarted; restarts
+	// for a changed secret are deferred until a window opens (optional, defaults
+	// to restarting right away)
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// RolloutStrategy controls how target workloads are restarted (optional,
+	// defaults to restarting all of them at once)
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+	// Rollback restores the previously synced secret data when the workloads
+	// fail to roll out with new data (optional). Without a Progressive
+	// rolloutStrategy, setting it restarts all target workloads in a single
+	// wave that is watched for stepTimeout (10m) before the change counts as
+	// rolled out.
+	Rollback *RollbackPolicy `json:"rollback,omitempty"`
+	// DeletionPolicy decides what happens to the target secret when the
+	// SecretRotation is deleted (defaults to Retain)
+	// +kubebuilder:default=Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// Adopt allows taking over an existing target secret that is not managed by
+	// this SecretRotation; secrets controlled by another owner are never taken over
+	Adopt bool `json:"adopt,omitempty"`
+}
+
+// DeletionPolicy decides what happens to a target secret when its SecretRotation is deleted
+// +kubebuilder:validation:Enum=Retain;Delete;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyRetain keeps the secret and its ownership label, so a new
+	// SecretRotation of the same name manages it again
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyDelete deletes the secret
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan keeps the secret and removes all ownership metadata
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+const (
+	// SecretRotationFinalizer lets the operator apply the deletion policy before a SecretRotation is removed
+	SecretRotationFinalizer = "secrets.github.com/target-secret"
+	// ManagedByLabel is set on target secrets to the name of the SecretRotation managing them
+	ManagedByLabel = "secrets.github.com/secretrotation"
+)
+
+// SecretSource is one of several KV secrets merged into a target secret
+// +kubebuilder:validation:XValidation:rule="has(self.vaultPath) || has(self.engine)",message="either vaultPath or engine must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.engine) || self.engine in ['kv-v1', 'kv-v2']",message="sources only support the kv-v1 and kv-v2 engines"
+// +kubebuilder:validation:XValidation:rule="!has(self.engine) || (has(self.mount) && has(self.path))",message="kv engines require mount and path"
+// +kubebuilder:validation:XValidation:rule="!has(self.version) || (has(self.engine) && self.engine == 'kv-v2')",message="version can only be pinned for the kv-v2 engine"
+type SecretSource struct {
+	// Name identifies the source in status and templates
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name        string `json:"name"`
+	VaultSource `json:",inline"`
+	// KeyMapping selects and renames the keys taken from this source (optional)
+	KeyMapping *KeyMapping `json:"keyMapping,omitempty"`
+	// Encoding configures how the values of this source are written (optional)
+	Encoding *ValueEncoding `json:"encoding,omitempty"`
+}
+
+// ConflictPolicy decides which source wins when several write the same secret key
+// +kubebuilder:validation:Enum=Error;FirstWins;LastWins
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyError fails the sync
+	ConflictPolicyError ConflictPolicy = "Error"
+	// ConflictPolicyFirstWins keeps the value of the source listed first
+	ConflictPolicyFirstWins ConflictPolicy = "FirstWins"
+	// ConflictPolicyLastWins keeps the value of the source listed last
+	ConflictPolicyLastWins ConflictPolicy = "LastWins"
+)
+
+// KeyMapping decides which Vault keys end up in the target secret and under which names.
+// Include and exclude patterns and renames refer to the keys in Vault; the prefix is added last.
+type KeyMapping struct {
+	// Include lists glob patterns (e.g., "db_*") of the Vault keys to copy (defaults to all keys)
+	Include []string `json:"include,omitempty"`
+	// Exclude lists glob patterns of Vault keys that are never copied, even if included
+	Exclude []string `json:"exclude,omitempty"`
+	// Rename maps Vault keys to the secret keys they are written to (e.g., password: DB_PASSWORD)
+	Rename map[string]string `json:"rename,omitempty"`
+	// Prefix is prepended to every secret key (e.g., "APP_")
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// ValueEncoding configures how Vault values are written to the target secret.
+// Strings are always written as is, numbers with all their digits and nested
+// maps and lists as JSON.
+type ValueEncoding struct {
+	// Base64 lists glob patterns of Vault keys whose values are base64 encoded
+	// binary data, such as keystores or keytabs, that is decoded into the secret
+	Base64 []string `json:"base64,omitempty"`
+}
+
+// SecretTemplate renders keys of the target secret, such as config files or
+// connection strings, from the Vault data
+type SecretTemplate struct {
+	// Data maps secret keys to Go text/template bodies. Templates see the Vault
+	// data as .Data and the mount, path, version and lease as .Metadata, and can
+	// use sprig-like helpers such as default, quote, b64enc, toJson and indent.
+	// +kubebuilder:validation:MinProperties=1
+	Data map[string]string `json:"data"`
+	// Merge keeps the (mapped) Vault keys in the secret next to the rendered
+	// ones; a rendered key replaces a Vault key of the same name. By default the
+	// secret only holds the rendered keys.
+	Merge bool `json:"merge,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period during which workloads may be restarted
+type MaintenanceWindow struct {
+	// Schedule is a cron expression for when the window opens (e.g., "0 2 * * 0")
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// Duration is how long the window stays open
+	Duration metav1.Duration `json:"duration"`
+}
+
+// RolloutStrategyType is how target workloads are restarted
+// +kubebuilder:validation:Enum=AllAtOnce;Progressive
+type RolloutStrategyType string
+
+const (
+	// RolloutStrategyAllAtOnce restarts every target workload right away
+	RolloutStrategyAllAtOnce RolloutStrategyType = "AllAtOnce"
+	// RolloutStrategyProgressive restarts target workloads in waves, each
+	// once the workloads of the previous wave rolled out
+	RolloutStrategyProgressive RolloutStrategyType = "Progressive"
+)
+
+// RolloutStrategy controls how target workloads are restarted
+type RolloutStrategy struct {
+	// Type is AllAtOnce or Progressive (defaults to AllAtOnce)
+	// +kubebuilder:default=AllAtOnce
+	Type RolloutStrategyType `json:"type,omitempty"`
+	// WaveSize is how many workloads a Progressive rollout restarts at a
+	// time, in the order of targetWorkloads followed by the discovered
+	// workloads (defaults to 1)
+	// +kubebuilder:validation:Minimum=1
+	WaveSize int32 `json:"waveSize,omitempty"`
+	// StepTimeout is how long the workloads of a wave may take to roll out
+	// before the rollout halts (defaults to 10m)
+	StepTimeout *metav1.Duration `json:"stepTimeout,omitempty"`
+}
+
+// RollbackPolicy configures automatic rollbacks of failed rollouts
+type RollbackPolicy struct {
+	// HistoryLimit is how many synced payloads are kept to roll back to (defaults to 3).
+	// The payloads are stored unencrypted in the <name>-rollback-history secret, so
+	// anyone who can read secrets in the namespace can read data that was
+	// already rotated out of Vault until it drops out of the history.
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default=3
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
+}
+
+// RotationPolicy configures how the operator rotates a secret in Vault
+// +kubebuilder:validation:XValidation:rule="has(self.interval) != has(self.schedule)",message="exactly one of interval or schedule must be set"
+type RotationPolicy struct {
+	// Interval is how long after the current Vault version was written the keys are regenerated
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Schedule is a cron expression for when the keys are regenerated (e.g., "0 2 * * 0");
+	// a rotation is due once the schedule fired after the current Vault version was written
+	Schedule string `json:"schedule,omitempty"`
+	// Keys are the keys of the Vault secret that receive new values
+	// +kubebuilder:validation:MinItems=1
+	Keys []string `json:"keys"`
+	// Generator configures how new values are generated
+	Generator PasswordGenerator `json:"generator,omitempty"`
+	// HistoryLimit is the number of rotations kept in status (defaults to 10)
+	// +kubebuilder:validation:Minimum=1
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+}
+
+// PasswordGenerator configures how new secret values are generated
+type PasswordGenerator struct {
+	// Length is the number of characters to generate (defaults to 32)
+	// +kubebuilder:validation:Minimum=8
+	// +kubebuilder:validation:Maximum=1024
+	Length *int32 `json:"length,omitempty"`
+	// Charset is the set of characters values are drawn from (defaults to letters and digits)
+	Charset string `json:"charset,omitempty"`
+	// PolicyName is a Vault password policy that generates the values instead;
+	// length and charset are ignored when it is set
+	PolicyName string `json:"policyName,omitempty"`
 }
 
 // SecretRotationStatus defines observed state (optional)
 type SecretRotationStatus struct {
+	// ObservedGeneration is the generation of the spec the status was computed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastRotation is when the target secret last changed.
+	// Deprecated: use lastChangeTime.
 	LastRotation metav1.Time `json:"lastRotation,omitempty"`
+	// LastSyncTime is when the target secret was last successfully synced from Vault
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// LastChangeTime is when the data of the target secret last changed
+	LastChangeTime *metav1.Time `json:"lastChangeTime,omitempty"`
 	// SecretChecksum is the checksum of the current secret data
 	SecretChecksum string `json:"secretChecksum,omitempty"`
 	// UpdatedWorkloads tracks which workloads were successfully updated
 	UpdatedWorkloads []string `json:"updatedWorkloads,omitempty"`
+	// DiscoveredWorkloads are the workloads targeted through workloadSelector
+	// or discoverWorkloads, in addition to targetWorkloads
+	DiscoveredWorkloads []WorkloadReference `json:"discoveredWorkloads,omitempty"`
+	// SyncedVersion describes the KV v2 secret version that was last synced
+	SyncedVersion *VaultSecretVersion `json:"syncedVersion,omitempty"`
+	// Sources reports what was synced from each of the sources, in spec order
+	Sources []SourceStatus `json:"sources,omitempty"`
+	// Lease tracks the lease of dynamic credentials written to the target secret
+	Lease *VaultLease `json:"lease,omitempty"`
+	// Certificate describes the certificate written to the target secret
+	Certificate *IssuedCertificate `json:"certificate,omitempty"`
+	// RotationHistory lists the most recent rotations performed by the operator, newest first
+	RotationHistory []RotationRecord `json:"rotationHistory,omitempty"`
+	// NextScheduledSync is when the secret will next be synced from Vault
+	NextScheduledSync *metav1.Time `json:"nextScheduledSync,omitempty"`
+	// NextRotation is when the secret will next be rotated in Vault
+	NextRotation *metav1.Time `json:"nextRotation,omitempty"`
+	// WorkloadRestartPending is true while target workloads still have to be restarted
+	// for the current secret, because of a maintenance window or a failed restart
+	WorkloadRestartPending bool `json:"workloadRestartPending,omitempty"`
+	// Rollout is the progress of the last Progressive rollout, or of the last
+	// rollout watched for a rollback
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+	// Rollback lists the payloads that can be rolled back to and the data
+	// that was rolled back from
+	Rollback *RollbackStatus `json:"rollback,omitempty"`
+	// Conditions represent the latest observations of the SecretRotation's state
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// RolloutPhase is the phase of a Progressive rollout
+type RolloutPhase string
+
+const (
+	// RolloutPhaseProgressing means a wave is rolling out or about to start
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhaseHalted means a wave failed or timed out; the remaining
+	// waves are not restarted until the secret changes again
+	RolloutPhaseHalted RolloutPhase = "Halted"
+	// RolloutPhaseCompleted means every wave rolled out
+	RolloutPhaseCompleted RolloutPhase = "Completed"
+)
+
+// RolloutStatus is the progress of a Progressive rollout
+type RolloutStatus struct {
+	// Checksum is the checksum of the secret being rolled out
+	Checksum string `json:"checksum"`
+	// Phase is Progressing, Halted or Completed
+	Phase RolloutPhase `json:"phase"`
+	// Wave is the wave that was started last, counting from 1
+	Wave int32 `json:"wave"`
+	// Waves is the number of waves of the rollout
+	Waves int32 `json:"waves"`
+	// Workloads are the workloads of the wave that was started last
+	Workloads []string `json:"workloads,omitempty"`
+	// Generations are the generations the restart gave the workloads of the
+	// wave that was started last, keyed like workloads. The wave is not
+	// complete before their controllers observed these generations.
+	Generations map[string]int64 `json:"generations,omitempty"`
+	// WaveStartTime is when the wave that was started last started
+	WaveStartTime *metav1.Time `json:"waveStartTime,omitempty"`
+	// Message describes the state of the wave
+	Message string `json:"message,omitempty"`
+	// RollingBack is true when the rollout restores previous secret data
+	RollingBack bool `json:"rollingBack,omitempty"`
+}
+
+// RollbackStatus tracks the payloads a SecretRotation can roll back to
+type RollbackStatus struct {
+	// HistorySecret is the secret holding the payloads listed in history
+	HistorySecret string `json:"historySecret"`
+	// History lists the last synced payloads, newest first
+	History []PayloadRecord `json:"history,omitempty"`
+	// RejectedChecksum is the checksum of the data that failed to roll out;
+	// it is not written to the target secret again until Vault holds different data
+	RejectedChecksum string `json:"rejectedChecksum,omitempty"`
+	// RejectedVersion is the KV v2 version holding the rejected data
+	RejectedVersion int `json:"rejectedVersion,omitempty"`
+	// LastRollbackTime is when the operator last rolled back
+	LastRollbackTime *metav1.Time `json:"lastRollbackTime,omitempty"`
+}
+
+// PayloadRecord describes a payload kept to roll back to
+type PayloadRecord struct {
+	// Checksum is the checksum of the payload, and its key in the history secret
+	Checksum string `json:"checksum"`
+	// SyncTime is when the payload was first synced
+	SyncTime metav1.Time `json:"syncTime"`
+	// Version is the KV v2 version the payload was read from
+	Version int `json:"version,omitempty"`
+}
+
+// SourceStatus is what was last synced from one of the sources of a SecretRotation
+type SourceStatus struct {
+	// Name is the name of the source
+	Name string `json:"name"`
+	// Path is where the source was read from in Vault
+	Path string `json:"path"`
+	// SyncedVersion describes the KV v2 secret version that was last synced
+	SyncedVersion *VaultSecretVersion `json:"syncedVersion,omitempty"`
+	// Keys are the secret keys whose values came from this source
+	Keys []string `json:"keys,omitempty"`
+}
+
+// VaultSecretVersion is the version metadata of a KV v2 secret
+type VaultSecretVersion struct {
+	// Version is the KV v2 version number
+	Version int `json:"version"`
+	// CreatedTime is when the version was written
+	CreatedTime *metav1.Time `json:"createdTime,omitempty"`
+	// DeletionTime is when the version was soft-deleted, if it was
+	DeletionTime *metav1.Time `json:"deletionTime,omitempty"`
+	// Destroyed is true if the version was permanently destroyed
+	Destroyed bool `json:"destroyed,omitempty"`
+}
+
+// VaultLease is the lease of dynamic credentials issued by Vault
+type VaultLease struct {
+	// LeaseID is the Vault lease ID
+	LeaseID string `json:"leaseID"`
+	// LeaseDuration is the lease duration in seconds granted when the credentials were issued
+	LeaseDuration int `json:"leaseDuration"`
+	// Renewable is true if Vault allows the lease to be renewed
+	Renewable bool `json:"renewable,omitempty"`
+	// IssueTime is when the credentials were issued
+	IssueTime metav1.Time `json:"issueTime"`
+	// LastRenewalTime is when the lease was last renewed
+	LastRenewalTime *metav1.Time `json:"lastRenewalTime,omitempty"`
+	// ExpireTime is when the lease currently expires
+	ExpireTime metav1.Time `json:"expireTime"`
+	// MaxTTLReached is true once Vault stopped extending the lease; the
+	// credentials are replaced before ExpireTime
+	MaxTTLReached bool `json:"maxTTLReached,omitempty"`
 }
 
+// IssuedCertificate describes a certificate issued by the Vault PKI engine
+type IssuedCertificate struct {
+	// SerialNumber is the serial number of the certificate
+	SerialNumber string `json:"serialNumber"`
+	// NotBefore is the start of the certificate's validity
+	NotBefore metav1.Time `json:"notBefore"`
+	// NotAfter is the end of the certificate's validity
+	NotAfter metav1.Time `json:"notAfter"`
+	// RenewalTime is when a new certificate will be issued
+	RenewalTime metav1.Time `json:"renewalTime"`
+}
+
+// RotationRecord describes a rotation performed by the operator
+type RotationRecord struct {
+	// Time is when the new values were written to Vault
+	Time metav1.Time `json:"time"`
+	// Version is the KV v2 version that was written
+	Version int `json:"version"`
+	// Keys are the keys that received new values
+	Keys []string `json:"keys"`
+}
+
+// Condition types reported on a SecretRotation
+const (
+	// ConditionReady is true when all other conditions are true
+	ConditionReady = "Ready"
+	// ConditionVaultReadable is true when the secret could be read from Vault
+	ConditionVaultReadable = "VaultReadable"
+	// ConditionSecretSynced is true when the target secret holds the data read from Vault
+	ConditionSecretSynced = "SecretSynced"
+	// ConditionWorkloadsRolled is true when all target workloads were restarted for the current secret
+	ConditionWorkloadsRolled = "WorkloadsRolled"
+	// ConditionTemplateRendered is true when the templates of the target secret rendered
+	ConditionTemplateRendered = "TemplateRendered"
+	// ConditionRotated is true when the last rotation of the secret in Vault succeeded
+	ConditionRotated = "Rotated"
+	// ConditionRolledBack is true when the target secret was restored to
+	// previous data after the new data failed to roll out
+	ConditionRolledBack = "RolledBack"
+)
+
+// Condition reasons reported on a SecretRotation
+const (
+	// ReasonVaultAuthFailed means the operator could not log in to Vault
+	ReasonVaultAuthFailed = "VaultAuthFailed"
+	// ReasonVaultReadFailed means the read request to Vault failed
+	ReasonVaultReadFailed = "VaultReadFailed"
+	// ReasonSecretNotFound means the Vault path holds no data
+	ReasonSecretNotFound = "SecretNotFound"
+	// ReasonSecretDeleted means the KV v2 version was soft-deleted or destroyed
+	ReasonSecretDeleted = "SecretDeleted"
+	// ReasonSecretRead means the secret was read from Vault
+	ReasonSecretRead = "SecretRead"
+	// ReasonRotationFailed means new values could not be generated or written to Vault
+	ReasonRotationFailed = "RotationFailed"
+	// ReasonRotationSucceeded means new values were written to Vault
+	ReasonRotationSucceeded = "RotationSucceeded"
+	// ReasonSecretSynced means the target secret is up to date
+	ReasonSecretSynced = "SecretSynced"
+	// ReasonSecretSyncFailed means the target secret could not be created or updated
+	ReasonSecretSyncFailed = "SecretSyncFailed"
+	// ReasonInvalidKeyMapping means the key mapping cannot be applied to the Vault data
+	ReasonInvalidKeyMapping = "InvalidKeyMapping"
+	// ReasonSourceConflict means several sources write the same secret key
+	ReasonSourceConflict = "SourceConflict"
+	// ReasonValueEncodingFailed means a Vault value could not be decoded or encoded into the secret
+	ReasonValueEncodingFailed = "ValueEncodingFailed"
+	// ReasonTemplateRendered means all templates of the target secret rendered
+	ReasonTemplateRendered = "TemplateRendered"
+	// ReasonTemplateFailed means a template of the target secret failed to parse or render
+	ReasonTemplateFailed = "TemplateFailed"
+	// ReasonSecretNotOwned means the target secret exists but is not managed by the SecretRotation
+	ReasonSecretNotOwned = "SecretNotOwned"
+	// ReasonSecretOwnerConflict means the target secret is controlled by another owner
+	ReasonSecretOwnerConflict = "SecretOwnerConflict"
+	// ReasonWorkloadsRolled means all target workloads were restarted
+	ReasonWorkloadsRolled = "WorkloadsRolled"
+	// ReasonWorkloadRolloutFailed means some target workloads could not be restarted
+	ReasonWorkloadRolloutFailed = "WorkloadRolloutFailed"
+	// ReasonWorkloadRestartPending means workload restarts wait for a maintenance window
+	ReasonWorkloadRestartPending = "WorkloadRestartPending"
+	// ReasonRolloutProgressing means a Progressive rollout is restarting target workloads in waves
+	ReasonRolloutProgressing = "RolloutProgressing"
+	// ReasonRolloutHalted means a wave of a Progressive rollout failed or timed out
+	ReasonRolloutHalted = "RolloutHalted"
+	// ReasonRolledBack means the previous secret data was restored after a failed rollout
+	ReasonRolledBack = "RolledBack"
+	// ReasonRollbackFailed means no previous secret data could be restored
+	ReasonRollbackFailed = "RollbackFailed"
+	// ReasonDataRejected means Vault still holds the data that was rolled back from
+	ReasonDataRejected = "DataRejected"
+	// ReasonReady means every other condition is true
+	ReasonReady = "Ready"
+	// ReasonReconciling means the SecretRotation has not been fully reconciled yet
+	ReasonReconciling = "Reconciling"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.targetSecret`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+//+kubebuilder:printcolumn:name="Last Change",type=date,JSONPath=`.status.lastChangeTime`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // SecretRotation is the Schema for the secretrotations API
 type SecretRotation struct {
@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VaultAuthMethod is a Vault auth method supported by VaultAuth
+// +kubebuilder:validation:Enum=kubernetes;appRole
+type VaultAuthMethod string
+
+const (
+	// VaultAuthMethodKubernetes logs in with a token of a service account in the VaultAuth namespace
+	VaultAuthMethodKubernetes VaultAuthMethod = "kubernetes"
+	// VaultAuthMethodAppRole logs in with a role_id and secret_id from a Secret
+	VaultAuthMethodAppRole VaultAuthMethod = "appRole"
+)
+
+// VaultKubernetesAuth configures the Vault Kubernetes auth method
+type VaultKubernetesAuth struct {
+	// Role is the Vault role to log in as
+	Role string `json:"role"`
+	// ServiceAccount is the service account whose token is sent to Vault (defaults to "default").
+	// It has to carry the VaultAuthServiceAccountAnnotation.
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// Audiences are the audiences requested for the service account token (optional)
+	Audiences []string `json:"audiences,omitempty"`
+}
+
+const (
+	// VaultAuthServiceAccountAnnotation must be set to "true" on a service account
+	// before the operator requests tokens for it to log in to Vault
+	VaultAuthServiceAccountAnnotation = "secrets.github.com/vault-auth"
+)
+
+// VaultAppRoleAuth configures the Vault AppRole auth method
+type VaultAppRoleAuth struct {
+	// SecretRef is the name of a Secret with role_id and secret_id keys
+	SecretRef string `json:"secretRef"`
+}
+
+// VaultAuthSpec defines how to log in to Vault
+type VaultAuthSpec struct {
+	// VaultConnectionRef is the name of a VaultConnection in the same namespace
+	// (optional, defaults to the operator's own Vault connection)
+	VaultConnectionRef string `json:"vaultConnectionRef,omitempty"`
+	// Method is the Vault auth method to log in with
+	Method VaultAuthMethod `json:"method"`
+	// MountPath is the path the auth method is mounted at (defaults to the method name)
+	MountPath string `json:"mountPath,omitempty"`
+	// Kubernetes configures the kubernetes auth method
+	Kubernetes *VaultKubernetesAuth `json:"kubernetes,omitempty"`
+	// AppRole configures the appRole auth method
+	AppRole *VaultAppRoleAuth `json:"appRole,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Method",type=string,JSONPath=`.spec.method`
+//+kubebuilder:printcolumn:name="Connection",type=string,JSONPath=`.spec.vaultConnectionRef`
+
+// VaultAuth is the Schema for the vaultauths API
+type VaultAuth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VaultAuthSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// VaultAuthList contains a list of VaultAuth
+type VaultAuthList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VaultAuth `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VaultAuth{}, &VaultAuthList{})
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VaultConnectionSpec defines how to reach a Vault server
+type VaultConnectionSpec struct {
+	// Address is the URL of the Vault server (e.g., https://vault.example.com:8200)
+	Address string `json:"address"`
+	// CABundle is a PEM encoded CA bundle used to verify the Vault server certificate
+	CABundle string `json:"caBundle,omitempty"`
+	// TLSServerName is the SNI host name to use when connecting to Vault (optional)
+	TLSServerName string `json:"tlsServerName,omitempty"`
+	// Namespace is the Vault Enterprise namespace sent in the X-Vault-Namespace header (optional)
+	Namespace string `json:"namespace,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Address",type=string,JSONPath=`.spec.address`
+//+kubebuilder:printcolumn:name="Vault Namespace",type=string,JSONPath=`.spec.namespace`
+
+// VaultConnection is the Schema for the vaultconnections API
+type VaultConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VaultConnectionSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// VaultConnectionList contains a list of VaultConnection
+type VaultConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VaultConnection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VaultConnection{}, &VaultConnectionList{})
+}
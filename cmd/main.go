@@ -1,19 +1,26 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"strings"
 
 	vault "github.com/hashicorp/vault/api"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
 	"github.com/Amogha-rao/secret-rotator-operator/internal/controller"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/vaultclient"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -29,8 +36,43 @@ func init() {
 }
 
 func main() {
-	var metricsAddr string
+	var metricsAddr, probeAddr string
+	var vaultAuthMethod, vaultAuthRole, vaultAuthMount, vaultTokenPath string
+	var appRoleIDFile, appRoleSecretIDFile, appRoleSecret string
+	var appRoleWrapped, vaultEvents, enableLeaderElection, crossNamespaceWorkloads bool
+	var vaultEventType, workloadKinds string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
+		"Enable leader election for the manager, so that only one replica reconciles at a time.")
+	// Keep logging in with VAULT_TOKEN for deployments that set it from before other methods existed
+	defaultAuthMethod := "kubernetes"
+	if os.Getenv("VAULT_TOKEN") != "" {
+		defaultAuthMethod = "token"
+	}
+	flag.StringVar(&vaultAuthMethod, "vault-auth-method", defaultAuthMethod,
+		"How to authenticate to Vault: \"kubernetes\", \"approle\" or \"token\" (reads VAULT_TOKEN). "+
+			"Defaults to \"token\" when VAULT_TOKEN is set and \"kubernetes\" otherwise.")
+	flag.StringVar(&vaultAuthRole, "vault-auth-role", "", "The Vault role to log in as with Kubernetes auth.")
+	flag.StringVar(&vaultAuthMount, "vault-auth-mount", "",
+		"The mount path of the Vault auth method. Defaults to the method name.")
+	flag.StringVar(&vaultTokenPath, "vault-sa-token-path", vaultclient.DefaultServiceAccountTokenPath,
+		"The projected service account token used for Vault Kubernetes auth.")
+	flag.StringVar(&appRoleIDFile, "vault-approle-role-id-file", "", "File containing the AppRole role_id.")
+	flag.StringVar(&appRoleSecretIDFile, "vault-approle-secret-id-file", "", "File containing the AppRole secret_id.")
+	flag.StringVar(&appRoleSecret, "vault-approle-secret", "",
+		"Secret (<namespace>/<name>) with role_id and secret_id keys, used when no files are given.")
+	flag.BoolVar(&appRoleWrapped, "vault-approle-secret-id-wrapped", false,
+		"The AppRole secret_id is a response-wrapping token that must be unwrapped before login.")
+	flag.BoolVar(&vaultEvents, "vault-events", false,
+		"Subscribe to Vault events (Vault 1.13+) to sync SecretRotations as soon as their path is written.")
+	flag.StringVar(&vaultEventType, "vault-events-type", vaultclient.DefaultEventType,
+		"The Vault event type pattern to subscribe to.")
+	flag.StringVar(&workloadKinds, "workload-kinds", "",
+		"Comma separated Kind.version.group list of workload kinds besides the built-in ones to watch "+
+			"(e.g. Rollout.v1alpha1.argoproj.io), so their drift is repaired right away.")
+	flag.BoolVar(&crossNamespaceWorkloads, "cross-namespace-workloads", false,
+		"Let the namespaceSelector of a SecretRotation's workloadSelector select workloads in other namespaces.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
@@ -40,6 +82,9 @@ func main() {
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
 		},
+		HealthProbeBindAddress: probeAddr,
+		LeaderElection:         enableLeaderElection,
+		LeaderElectionID:       "secret-rotator-operator.secrets.github.com",
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -54,18 +99,121 @@ func main() {
 		setupLog.Error(err, "unable to initialize Vault client")
 		os.Exit(1)
 	}
-	vaultClient.SetToken(os.Getenv("VAULT_TOKEN")) // Consider using Kubernetes auth later
+
+	var vaultAuth vaultclient.Authenticator
+	switch vaultAuthMethod {
+	case "token":
+		vaultAuth = &vaultclient.TokenAuth{Token: os.Getenv("VAULT_TOKEN")}
+	case "kubernetes":
+		vaultAuth = &vaultclient.KubernetesAuth{
+			Role:      vaultAuthRole,
+			MountPath: vaultAuthMount,
+			TokenPath: vaultTokenPath,
+		}
+	case "approle":
+		appRole := &vaultclient.AppRoleAuth{
+			MountPath:       vaultAuthMount,
+			RoleIDFile:      appRoleIDFile,
+			SecretIDFile:    appRoleSecretIDFile,
+			Reader:          mgr.GetAPIReader(),
+			WrappedSecretID: appRoleWrapped,
+		}
+		if appRoleSecret != "" {
+			namespace, name, found := strings.Cut(appRoleSecret, "/")
+			if !found {
+				setupLog.Error(nil, "--vault-approle-secret must be <namespace>/<name>", "value", appRoleSecret)
+				os.Exit(1)
+			}
+			appRole.SecretRef = types.NamespacedName{Namespace: namespace, Name: name}
+		}
+		vaultAuth = appRole
+	default:
+		setupLog.Error(nil, "unsupported Vault auth method", "method", vaultAuthMethod)
+		os.Exit(1)
+	}
+
+	// Keep the Vault token alive for as long as the manager runs
+	tokenManager := &vaultclient.TokenManager{
+		Client: vaultClient,
+		Auth:   vaultAuth,
+		Log:    ctrl.Log.WithName("vault"),
+	}
+	if err := mgr.Add(tokenManager); err != nil {
+		setupLog.Error(err, "unable to set up Vault token manager")
+		os.Exit(1)
+	}
+
+	// Enqueue SecretRotations as soon as Vault reports a write to their path
+	var vaultEventCh chan event.TypedGenericEvent[vaultclient.Event]
+	if vaultEvents {
+		vaultEventCh = make(chan event.TypedGenericEvent[vaultclient.Event])
+		subscriber := &vaultclient.EventSubscriber{
+			Client:    vaultClient,
+			EventType: vaultEventType,
+			Log:       ctrl.Log.WithName("vault-events"),
+			OnEvent: func(ctx context.Context, e vaultclient.Event) {
+				select {
+				case vaultEventCh <- event.TypedGenericEvent[vaultclient.Event]{Object: e}:
+				case <-ctx.Done():
+				}
+			},
+		}
+		if err := mgr.Add(subscriber); err != nil {
+			setupLog.Error(err, "unable to set up Vault event subscriber")
+			os.Exit(1)
+		}
+	}
+
+	var watchedKinds []schema.GroupVersionKind
+	for _, kind := range strings.Split(workloadKinds, ",") {
+		if kind = strings.TrimSpace(kind); kind == "" {
+			continue
+		}
+		gvk, _ := schema.ParseKindArg(kind)
+		if gvk == nil {
+			setupLog.Error(nil, "--workload-kinds entries must be Kind.version.group", "value", kind)
+			os.Exit(1)
+		}
+		watchedKinds = append(watchedKinds, *gvk)
+	}
 
 	if err = (&controller.SecretRotationReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Log:    ctrl.Log.WithName("controllers").WithName("SecretRotation"),
-		Vault:  vaultClient,
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		Log:        ctrl.Log.WithName("controllers").WithName("SecretRotation"),
+		Vault:      vaultClient,
+		VaultToken: tokenManager,
+		VaultClients: &vaultclient.ClientCache{
+			Base: vaultConfig,
+		},
+		VaultEvents:             vaultEventCh,
+		WorkloadKinds:           watchedKinds,
+		CrossNamespaceWorkloads: crossNamespaceWorkloads,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SecretRotation")
 		os.Exit(1)
 	}
 
+	if err = (&controller.ClusterSecretRotationReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		Log:        ctrl.Log.WithName("controllers").WithName("ClusterSecretRotation"),
+		Vault:      vaultClient,
+		VaultToken: tokenManager,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterSecretRotation")
+		os.Exit(1)
+	}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("vault-token", tokenManager.Checker); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fieldManager owns the fields the operator writes to secrets and workloads
+const fieldManager = "secret-rotator-operator"
+
+// applySecret writes secret with server-side apply, so the fields the
+// operator owns are listed under fieldManager in its managedFields. Keys
+// another field manager added are removed afterwards, leaving exactly the
+// keys of secret.
+func applySecret(ctx context.Context, c client.Client, secret *corev1.Secret) error {
+	secret.APIVersion = "v1"
+	secret.Kind = "Secret"
+	secret.ManagedFields = nil
+	data := secret.Data
+	if err := c.Patch(ctx, secret, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+
+	foreign := make(map[string]interface{})
+	for key := range secret.Data {
+		if _, ok := data[key]; !ok {
+			foreign[key] = nil
+		}
+	}
+	if len(foreign) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{"data": foreign})
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, secret, client.RawPatch(types.MergePatchType, patch), client.FieldOwner(fieldManager))
+}
+
+// releasePatch returns a merge patch that leaves owners as the owner
+// references of an object and, when dropLabel is set, removes that label
+func releasePatch(owners []metav1.OwnerReference, dropLabel string) ([]byte, error) {
+	// A nil slice encodes as null, which clears the list
+	metadata := map[string]interface{}{"ownerReferences": owners}
+	if dropLabel != "" {
+		metadata["labels"] = map[string]interface{}{dropLabel: nil}
+	}
+	return json.Marshal(map[string]interface{}{"metadata": metadata})
+}
+
+// releaseSecret drops the owner reference to owner from secret, and the
+// label dropLabel when set, with a merge patch that leaves every other field
+// as it is
+func releaseSecret(ctx context.Context, c client.Client, secret *corev1.Secret, owner types.UID, dropLabel string) error {
+	patch, err := releasePatch(removeOwner(secret.OwnerReferences, owner), dropLabel)
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, secret, client.RawPatch(types.MergePatchType, patch), client.FieldOwner(fieldManager))
+}
+
+// secretDataEqual reports whether two secret payloads hold the same keys and values
+func secretDataEqual(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !bytes.Equal(v, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// podTemplatePatch returns a merge patch setting the annotation key of the
+// pod template at path to value
+func podTemplatePatch(path []string, key, value string) ([]byte, error) {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{key: value},
+		},
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		patch = map[string]interface{}{path[i]: patch}
+	}
+	return json.Marshal(patch)
+}
+
+// patchPodTemplateAnnotation sets the pod template annotation key of obj to
+// value with a merge patch, leaving the fields other controllers manage
+// alone. path leads to the pod template in obj.
+func patchPodTemplateAnnotation(ctx context.Context, c client.Client, obj client.Object, path []string, key, value string) error {
+	patch, err := podTemplatePatch(path, key, value)
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch), client.FieldOwner(fieldManager))
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+var _ = Describe("releaseSecret", func() {
+	ctx := context.Background()
+
+	var secret *corev1.Secret
+	var c client.Client
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app",
+				Namespace: "default",
+				Labels:    map[string]string{secretsv1alpha1.ManagedByLabel: "app", "team": "a"},
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other"},
+					{APIVersion: secretsv1alpha1.GroupVersion.String(), Kind: "SecretRotation", Name: "app", UID: "sr"},
+				},
+			},
+			Data: map[string][]byte{"password": []byte("s3cr3t")},
+		}
+		c = fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(secret).Build()
+	})
+
+	It("only drops the owner reference to the SecretRotation", func() {
+		Expect(releaseSecret(ctx, c, secret, "sr", "")).To(Succeed())
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+		Expect(secret.OwnerReferences[0].UID).To(BeEquivalentTo("other"))
+		Expect(secret.Labels).To(HaveKeyWithValue(secretsv1alpha1.ManagedByLabel, "app"))
+		Expect(secret.Data).To(HaveKey("password"))
+	})
+
+	It("drops the label as well and keeps the other labels", func() {
+		Expect(releaseSecret(ctx, c, secret, "sr", secretsv1alpha1.ManagedByLabel)).To(Succeed())
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		Expect(secret.Labels).To(Equal(map[string]string{"team": "a"}))
+	})
+
+	It("does not conflict with a write made after the secret was read", func() {
+		stale := secret.DeepCopy()
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		secret.Labels["team"] = "b"
+		Expect(c.Update(ctx, secret)).To(Succeed())
+
+		Expect(releaseSecret(ctx, c, stale, "sr", "")).To(Succeed())
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		Expect(secret.Labels).To(HaveKeyWithValue("team", "b"))
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+	})
+})
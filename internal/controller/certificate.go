@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/vaultclient"
+)
+
+const (
+	// tlsCAKey is the key of the root CA in kubernetes.io/tls secrets
+	tlsCAKey = "ca.crt"
+	// defaultRenewAtPercent is the share of a certificate's lifetime after which it is renewed
+	defaultRenewAtPercent = 67
+)
+
+// reconcileCertificate returns true while the current certificate of sr
+// matches the spec and is not yet due for renewal
+func (r *SecretRotationReconciler) reconcileCertificate(ctx context.Context, sr *secretsv1alpha1.SecretRotation) (bool, error) {
+	issued := sr.Status.Certificate
+	if issued == nil || sr.Spec.PKI == nil {
+		return false, nil
+	}
+
+	// Check the certificate as issued, the target secret may have been edited
+	reads, err := r.storedCredentials(ctx, sr)
+	if err != nil || reads == nil {
+		return false, nil
+	}
+	certificate, _ := reads[0].secret.Data[corev1.TLSCertKey].(string)
+	cert, err := vaultclient.ParseCertificate(certificate)
+	if err != nil || formatSerial(cert.SerialNumber) != issued.SerialNumber {
+		return false, nil
+	}
+
+	// Reissue when the requested names changed
+	if cert.Subject.CommonName != sr.Spec.PKI.CommonName {
+		return false, nil
+	}
+	for _, name := range sr.Spec.PKI.AltNames {
+		if !slices.Contains(cert.DNSNames, name) && !slices.Contains(cert.EmailAddresses, name) {
+			return false, nil
+		}
+	}
+	for _, ip := range sr.Spec.PKI.IPSANs {
+		if !slices.ContainsFunc(cert.IPAddresses, func(addr net.IP) bool { return addr.String() == ip }) {
+			return false, nil
+		}
+	}
+
+	return time.Now().Before(issued.RenewalTime.Time), nil
+}
+
+// certificateRequest converts the pki spec of a VaultSource into an issue request
+func certificateRequest(pki *secretsv1alpha1.PKICertificate) vaultclient.CertificateRequest {
+	if pki == nil {
+		return vaultclient.CertificateRequest{}
+	}
+	req := vaultclient.CertificateRequest{
+		CommonName: pki.CommonName,
+		AltNames:   pki.AltNames,
+		IPSANs:     pki.IPSANs,
+	}
+	if pki.TTL != nil {
+		req.TTL = pki.TTL.Duration
+	}
+	return req
+}
+
+// issuedCertificate describes the certificate in data for the SecretRotation status
+func issuedCertificate(data map[string][]byte, pki *secretsv1alpha1.PKICertificate) *secretsv1alpha1.IssuedCertificate {
+	cert, err := vaultclient.ParseCertificate(string(data[corev1.TLSCertKey]))
+	if err != nil {
+		return nil
+	}
+	renewAtPercent := int32(defaultRenewAtPercent)
+	if pki != nil && pki.RenewAtPercent != nil {
+		renewAtPercent = *pki.RenewAtPercent
+	}
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return &secretsv1alpha1.IssuedCertificate{
+		SerialNumber: formatSerial(cert.SerialNumber),
+		NotBefore:    metav1.NewTime(cert.NotBefore),
+		NotAfter:     metav1.NewTime(cert.NotAfter),
+		RenewalTime:  metav1.NewTime(cert.NotBefore.Add(lifetime * time.Duration(renewAtPercent) / 100)),
+	}
+}
+
+// formatSerial formats a certificate serial number the way Vault does (e.g. "1a:2b:3c")
+func formatSerial(serial *big.Int) string {
+	parts := make([]string, 0, len(serial.Bytes()))
+	for _, b := range serial.Bytes() {
+		parts = append(parts, fmt.Sprintf("%02x", b))
+	}
+	return strings.Join(parts, ":")
+}
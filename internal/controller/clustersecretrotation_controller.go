@@ -0,0 +1,412 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	vault "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/secretdata"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/vaultclient"
+)
+
+// +kubebuilder:rbac:groups=secrets.github.com,resources=clustersecretrotations,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=secrets.github.com,resources=clustersecretrotations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=secrets.github.com,resources=clustersecretrotations/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// clusterOwnerField indexes secrets by the UID of the ClusterSecretRotation controlling them
+const clusterOwnerField = ".metadata.ownerReferences.clusterSecretRotation"
+
+// ClusterSecretRotationReconciler copies a Vault secret into every namespace
+// selected by a ClusterSecretRotation. Copies are owned by the
+// ClusterSecretRotation, so they are garbage collected when it is deleted.
+type ClusterSecretRotationReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Vault  *vault.Client
+	Scheme *runtime.Scheme
+	// VaultToken reports whether Vault holds a valid token (optional)
+	VaultToken *vaultclient.TokenManager
+	// Recorder records events on ClusterSecretRotations
+	// (defaults to the manager's recorder in SetupWithManager)
+	Recorder record.EventRecorder
+}
+
+func (r *ClusterSecretRotationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("clustersecretrotation", req.Name)
+
+	var csr secretsv1alpha1.ClusterSecretRotation
+	if err := r.Get(ctx, req.NamespacedName, &csr); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// The copies go away with the ClusterSecretRotation through their owner reference
+	if !csr.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if r.VaultToken != nil {
+		if err := r.VaultToken.Err(); err != nil {
+			log.Error(err, "not authenticated to Vault")
+			return r.retryLater(ctx, &csr, secretsv1alpha1.ConditionVaultReadable, secretsv1alpha1.ReasonVaultAuthFailed, err)
+		}
+	}
+
+	// Fetch and transform secret data from Vault, exactly like a SecretRotation
+	sources := secretSources(&csr.Spec.SecretDataSpec)
+	source := describeSources(sources)
+	reads, reason, err := readSources(ctx, r.Vault, sources)
+	if err != nil {
+		log.Error(err, "failed to read from Vault", "source", source)
+		return r.retryLater(ctx, &csr, secretsv1alpha1.ConditionVaultReadable, reason, err)
+	}
+	meta.SetStatusCondition(&csr.Status.Conditions, metav1.Condition{
+		Type:               secretsv1alpha1.ConditionVaultReadable,
+		Status:             metav1.ConditionTrue,
+		Reason:             secretsv1alpha1.ReasonSecretRead,
+		Message:            fmt.Sprintf("Read secret from Vault at %s", source),
+		ObservedGeneration: csr.Generation,
+	})
+
+	data, owners, reason, err := mergeSources(reads, csr.Spec.ConflictPolicy)
+	if err != nil {
+		log.Error(err, "failed to merge Vault data", "source", source)
+		return r.retryLater(ctx, &csr, secretsv1alpha1.ConditionSecretSynced, reason, err)
+	}
+	if csr.Spec.Template != nil {
+		data, err = renderTemplate(&csr.Spec.SecretDataSpec, reads, data)
+		if err != nil {
+			log.Error(err, "failed to render secret template")
+			return r.retryLater(ctx, &csr, secretsv1alpha1.ConditionTemplateRendered, secretsv1alpha1.ReasonTemplateFailed, err)
+		}
+		meta.SetStatusCondition(&csr.Status.Conditions, metav1.Condition{
+			Type:               secretsv1alpha1.ConditionTemplateRendered,
+			Status:             metav1.ConditionTrue,
+			Reason:             secretsv1alpha1.ReasonTemplateRendered,
+			Message:            fmt.Sprintf("Rendered %d templated keys", len(csr.Spec.Template.Data)),
+			ObservedGeneration: csr.Generation,
+		})
+	} else {
+		meta.RemoveStatusCondition(&csr.Status.Conditions, secretsv1alpha1.ConditionTemplateRendered)
+	}
+	secretData, err := secretdata.EncodeAll(data)
+	if err != nil {
+		log.Error(err, "failed to encode Vault data", "source", source)
+		return r.retryLater(ctx, &csr, secretsv1alpha1.ConditionSecretSynced, secretsv1alpha1.ReasonValueEncodingFailed, err)
+	}
+
+	// Find the selected namespaces
+	selector, err := metav1.LabelSelectorAsSelector(&csr.Spec.NamespaceSelector)
+	if err != nil {
+		return r.retryLater(ctx, &csr, secretsv1alpha1.ConditionSecretSynced, secretsv1alpha1.ReasonNamespaceSyncFailed,
+			fmt.Errorf("invalid namespaceSelector: %w", err))
+	}
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Copy the secret into every selected namespace
+	now := metav1.Now()
+	previous := make(map[string]secretsv1alpha1.NamespaceSyncStatus, len(csr.Status.Namespaces))
+	for _, status := range csr.Status.Namespaces {
+		previous[status.Namespace] = status
+	}
+	selected := make(map[string]bool, len(namespaces.Items))
+	statuses := make([]secretsv1alpha1.NamespaceSyncStatus, 0, len(namespaces.Items))
+	var failed []string
+	for _, ns := range namespaces.Items {
+		if !ns.DeletionTimestamp.IsZero() {
+			continue
+		}
+		selected[ns.Name] = true
+
+		status := secretsv1alpha1.NamespaceSyncStatus{Namespace: ns.Name, Synced: true, LastSyncTime: &now}
+		if reason, err := r.syncNamespace(ctx, log, &csr, ns.Name, secretData, source); err != nil {
+			log.Error(err, "failed to sync secret", "namespace", ns.Name)
+			r.Recorder.Eventf(&csr, corev1.EventTypeWarning, reason, "Namespace %s: %v", ns.Name, err)
+			status = secretsv1alpha1.NamespaceSyncStatus{
+				Namespace:    ns.Name,
+				Reason:       reason,
+				Message:      err.Error(),
+				LastSyncTime: previous[ns.Name].LastSyncTime,
+			}
+			failed = append(failed, ns.Name)
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Namespace < statuses[j].Namespace })
+
+	// Remove the copies from namespaces that are no longer selected
+	pruneErr := r.pruneCopies(ctx, log, &csr, selected)
+	if pruneErr != nil {
+		log.Error(pruneErr, "failed to remove secrets from namespaces that are no longer selected")
+	}
+
+	// Update status with sync and change times and checksum
+	checksum := calculateSecretChecksum(secretData)
+	if csr.Status.SecretChecksum != checksum {
+		csr.Status.LastChangeTime = &now
+	}
+	csr.Status.SecretChecksum = checksum
+	csr.Status.SyncedVersion = nil
+	csr.Status.Sources = nil
+	if len(csr.Spec.Sources) == 0 {
+		csr.Status.SyncedVersion = syncedVersion(reads[0].secret.VersionMetadata)
+	} else {
+		csr.Status.Sources = sourceStatuses(reads, owners)
+	}
+	csr.Status.Namespaces = statuses
+	csr.Status.SyncedNamespaces = int32(len(statuses) - len(failed))
+
+	interval := intervalOrDefault(csr.Spec.RefreshInterval, defaultRefreshInterval)
+	synced := metav1.Condition{
+		Type:               secretsv1alpha1.ConditionSecretSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             secretsv1alpha1.ReasonSecretSynced,
+		Message:            fmt.Sprintf("Secret %s is up to date in %d namespaces", csr.Spec.TargetSecret, len(statuses)),
+		ObservedGeneration: csr.Generation,
+	}
+	switch {
+	case len(failed) > 0:
+		synced.Status = metav1.ConditionFalse
+		synced.Reason = secretsv1alpha1.ReasonNamespaceSyncFailed
+		synced.Message = fmt.Sprintf("Secret %s could not be synced to %s", csr.Spec.TargetSecret, strings.Join(failed, ", "))
+	case pruneErr != nil:
+		synced.Status = metav1.ConditionFalse
+		synced.Reason = secretsv1alpha1.ReasonNamespaceSyncFailed
+		synced.Message = fmt.Sprintf("Failed to remove secret %s from namespaces that are no longer selected: %v", csr.Spec.TargetSecret, pruneErr)
+	default:
+		csr.Status.LastSyncTime = &now
+	}
+	if synced.Status != metav1.ConditionTrue {
+		interval = intervalOrDefault(csr.Spec.RetryInterval, defaultRetryInterval)
+	}
+	meta.SetStatusCondition(&csr.Status.Conditions, synced)
+	csr.Status.NextScheduledSync = &metav1.Time{Time: now.Add(interval)}
+
+	log.Info("Synced secret to namespaces", "secret", csr.Spec.TargetSecret, "namespaces", len(statuses), "failed", len(failed))
+	return ctrl.Result{RequeueAfter: interval}, r.saveStatus(ctx, &csr)
+}
+
+// syncNamespace creates or updates the copy of the secret in namespace. It
+// returns the reason for the failure alongside any error.
+func (r *ClusterSecretRotationReconciler) syncNamespace(ctx context.Context, log logr.Logger, csr *secretsv1alpha1.ClusterSecretRotation, namespace string, data map[string][]byte, source string) (string, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: csr.Spec.TargetSecret}, secret)
+	created := kerrors.IsNotFound(err)
+	if err != nil && !created {
+		return secretsv1alpha1.ReasonSecretSyncFailed, err
+	}
+
+	if !created {
+		// Never take over a Secret managed by someone else
+		if owner := metav1.GetControllerOf(secret); owner != nil && owner.UID != csr.UID {
+			return secretsv1alpha1.ReasonSecretOwnerConflict,
+				fmt.Errorf("secret %s is controlled by %s %s", secret.Name, owner.Kind, owner.Name)
+		}
+		// The controller reference proves ownership on its own; a copy that
+		// lost its label gets it back below
+		labeled := secret.Labels[secretsv1alpha1.ClusterManagedByLabel] == csr.Name
+		if !labeled && !metav1.IsControlledBy(secret, csr) {
+			return secretsv1alpha1.ReasonSecretNotOwned,
+				fmt.Errorf("secret %s exists and is not managed by this ClusterSecretRotation", secret.Name)
+		}
+		if labeled && secretDataEqual(secret.Data, data) {
+			return "", nil
+		}
+	}
+
+	desired := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      csr.Spec.TargetSecret,
+			Namespace: namespace,
+			Labels:    map[string]string{secretsv1alpha1.ClusterManagedByLabel: csr.Name},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+	if err := controllerutil.SetControllerReference(csr, desired, r.Scheme); err != nil {
+		return secretsv1alpha1.ReasonSecretSyncFailed, err
+	}
+	if err := applySecret(ctx, r.Client, desired); err != nil {
+		return secretsv1alpha1.ReasonSecretSyncFailed, err
+	}
+
+	if created {
+		log.Info("Created Kubernetes Secret", "namespace", namespace, "secret", csr.Spec.TargetSecret)
+		secretUpdates.WithLabelValues(namespace, csr.Name, "create").Inc()
+		r.Recorder.Eventf(csr, corev1.EventTypeNormal, eventSecretCreated,
+			"Created secret %s in namespace %s from Vault at %s", csr.Spec.TargetSecret, namespace, source)
+		return "", nil
+	}
+	log.Info("Updated Kubernetes Secret", "namespace", namespace, "secret", csr.Spec.TargetSecret)
+	secretUpdates.WithLabelValues(namespace, csr.Name, "update").Inc()
+	r.Recorder.Eventf(csr, corev1.EventTypeNormal, eventSecretUpdated,
+		"Updated secret %s in namespace %s from Vault at %s", csr.Spec.TargetSecret, namespace, source)
+	return "", nil
+}
+
+// pruneCopies deletes the copies of the secret of csr outside the selected
+// namespaces, and copies left behind under a previous targetSecret name
+func (r *ClusterSecretRotationReconciler) pruneCopies(ctx context.Context, log logr.Logger, csr *secretsv1alpha1.ClusterSecretRotation, selected map[string]bool) error {
+	// Look copies up by their controller reference, which outlives a removed label
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, client.MatchingFields{clusterOwnerField: string(csr.UID)}); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if selected[secret.Namespace] && secret.Name == csr.Spec.TargetSecret {
+			continue
+		}
+		if err := r.Delete(ctx, secret); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("Deleted Kubernetes Secret", "namespace", secret.Namespace, "secret", secret.Name)
+		r.Recorder.Eventf(csr, corev1.EventTypeNormal, eventSecretDeleted,
+			"Deleted secret %s from namespace %s, which is no longer selected", secret.Name, secret.Namespace)
+	}
+	return nil
+}
+
+// retryLater records a failed sync in the status of csr and requeues it after its retry interval
+func (r *ClusterSecretRotationReconciler) retryLater(ctx context.Context, csr *secretsv1alpha1.ClusterSecretRotation, conditionType, reason string, err error) (ctrl.Result, error) {
+	r.Recorder.Event(csr, corev1.EventTypeWarning, reason, err.Error())
+	retry := intervalOrDefault(csr.Spec.RetryInterval, defaultRetryInterval)
+	csr.Status.NextScheduledSync = &metav1.Time{Time: time.Now().Add(retry)}
+	meta.SetStatusCondition(&csr.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            err.Error(),
+		ObservedGeneration: csr.Generation,
+	})
+	return ctrl.Result{RequeueAfter: retry}, r.saveStatus(ctx, csr)
+}
+
+// saveStatus summarizes the conditions of csr in its Ready condition and saves its status
+func (r *ClusterSecretRotationReconciler) saveStatus(ctx context.Context, csr *secretsv1alpha1.ClusterSecretRotation) error {
+	csr.Status.ObservedGeneration = csr.Generation
+	ready := metav1.Condition{
+		Type:               secretsv1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             secretsv1alpha1.ReasonReady,
+		Message:            fmt.Sprintf("Secret %s is synced to %d namespaces", csr.Spec.TargetSecret, csr.Status.SyncedNamespaces),
+		ObservedGeneration: csr.Generation,
+	}
+	for _, conditionType := range []string{
+		secretsv1alpha1.ConditionVaultReadable,
+		secretsv1alpha1.ConditionTemplateRendered,
+		secretsv1alpha1.ConditionSecretSynced,
+	} {
+		if condition := meta.FindStatusCondition(csr.Status.Conditions, conditionType); condition != nil && condition.Status != metav1.ConditionTrue {
+			ready.Status = metav1.ConditionFalse
+			ready.Reason = condition.Reason
+			ready.Message = condition.Message
+			break
+		}
+	}
+	meta.SetStatusCondition(&csr.Status.Conditions, ready)
+	return r.Status().Update(ctx, csr)
+}
+
+// clusterSecretRotationsForNamespace maps a namespace to the
+// ClusterSecretRotations that select it or still have a copy in it
+func (r *ClusterSecretRotationReconciler) clusterSecretRotationsForNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	var csrs secretsv1alpha1.ClusterSecretRotationList
+	if err := r.List(ctx, &csrs); err != nil {
+		r.Log.Error(err, "failed to list ClusterSecretRotations", "namespace", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, csr := range csrs.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&csr.Spec.NamespaceSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(obj.GetLabels())) || syncedTo(&csr, obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: csr.Name}})
+		}
+	}
+	return requests
+}
+
+// syncedTo reports whether csr reported a copy in namespace
+func syncedTo(csr *secretsv1alpha1.ClusterSecretRotation, namespace string) bool {
+	for _, status := range csr.Status.Namespaces {
+		if status.Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterOwnerKeys returns the UID of the ClusterSecretRotation controlling obj, if any
+func clusterOwnerKeys(obj client.Object) []string {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil || owner.Kind != "ClusterSecretRotation" || owner.APIVersion != secretsv1alpha1.GroupVersion.String() {
+		return nil
+	}
+	return []string{string(owner.UID)}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterSecretRotationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("clustersecretrotation-controller")
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Secret{}, clusterOwnerField,
+		clusterOwnerKeys); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&secretsv1alpha1.ClusterSecretRotation{},
+			// Status updates must not cut the scheduled requeue short
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Repair copies changed or deleted behind the operator's back
+		Owns(&corev1.Secret{}).
+		// Pick up new namespaces and namespaces whose labels changed
+		Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.clusterSecretRotationsForNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+var _ = Describe("ClusterSecretRotation copy ownership", func() {
+	ctx := context.Background()
+	data := map[string][]byte{"password": []byte("s3cr3t")}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(secretsv1alpha1.AddToScheme(scheme))
+
+	var r *ClusterSecretRotationReconciler
+	var csr *secretsv1alpha1.ClusterSecretRotation
+	newReconciler := func(objs ...client.Object) {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+			WithIndex(&corev1.Secret{}, clusterOwnerField, clusterOwnerKeys).
+			// The fake client does not implement server-side apply
+			WithInterceptorFuncs(interceptor.Funcs{Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if patch.Type() != types.ApplyPatchType {
+					return c.Patch(ctx, obj, patch, opts...)
+				}
+				current := &corev1.Secret{}
+				if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); kerrors.IsNotFound(err) {
+					return c.Create(ctx, obj)
+				}
+				obj.SetResourceVersion(current.ResourceVersion)
+				return c.Update(ctx, obj)
+			}}).Build()
+		r = &ClusterSecretRotationReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	}
+	copyIn := func(namespace string, owned bool) *corev1.Secret {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: namespace}, Data: data}
+		if owned {
+			Expect(controllerutil.SetControllerReference(csr, secret, scheme)).To(Succeed())
+		}
+		return secret
+	}
+
+	BeforeEach(func() {
+		csr = &secretsv1alpha1.ClusterSecretRotation{ObjectMeta: metav1.ObjectMeta{Name: "registry", UID: "csr"}}
+		csr.Spec.TargetSecret = "registry"
+	})
+
+	It("takes back a copy it controls that lost its label", func() {
+		newReconciler(copyIn("team-a", true))
+
+		reason, err := r.syncNamespace(ctx, logr.Discard(), csr, "team-a", data, "secret/registry")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+
+		secret := &corev1.Secret{}
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "team-a", Name: "registry"}, secret)).To(Succeed())
+		Expect(secret.Labels).To(HaveKeyWithValue(secretsv1alpha1.ClusterManagedByLabel, "registry"))
+	})
+
+	It("leaves a secret it neither labelled nor controls alone", func() {
+		newReconciler(copyIn("team-a", false))
+
+		reason, err := r.syncNamespace(ctx, logr.Discard(), csr, "team-a", data, "secret/registry")
+		Expect(err).To(HaveOccurred())
+		Expect(reason).To(Equal(secretsv1alpha1.ReasonSecretNotOwned))
+	})
+
+	It("prunes a copy it controls that lost its label", func() {
+		newReconciler(copyIn("team-a", true), copyIn("team-b", true), copyIn("team-c", false))
+
+		Expect(r.pruneCopies(ctx, logr.Discard(), csr, map[string]bool{"team-a": true})).To(Succeed())
+
+		secret := &corev1.Secret{}
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "team-a", Name: "registry"}, secret)).To(Succeed())
+		err := r.Get(ctx, client.ObjectKey{Namespace: "team-b", Name: "registry"}, secret)
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "team-c", Name: "registry"}, secret)).To(Succeed())
+	})
+})
@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+// credentialsKey is the key of the credentials secret holding the Vault data
+const credentialsKey = "credentials.json"
+
+// issuedCredentials is the Vault data of dynamic credentials or a certificate
+type issuedCredentials struct {
+	// ID is the lease ID or certificate serial number the data was issued with
+	ID   string                 `json:"id"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// credentialsSecretName returns the name of the secret holding the Vault data
+// of the current credentials of sr
+func credentialsSecretName(sr *secretsv1alpha1.SecretRotation) string {
+	return sr.Name + "-vault-credentials"
+}
+
+// credentialsID returns the lease ID or certificate serial number of the
+// current credentials of sr, or "" when it has none
+func credentialsID(sr *secretsv1alpha1.SecretRotation) string {
+	switch {
+	case sr.Status.Lease != nil:
+		return sr.Status.Lease.LeaseID
+	case sr.Status.Certificate != nil:
+		return sr.Status.Certificate.SerialNumber
+	}
+	return ""
+}
+
+// storeCredentials keeps data, the Vault data of the current credentials of
+// sr, so the target secret can be rendered again without issuing new ones
+func (r *SecretRotationReconciler) storeCredentials(ctx context.Context, sr *secretsv1alpha1.SecretRotation, data map[string]interface{}) error {
+	payload, err := json.Marshal(issuedCredentials{ID: credentialsID(sr), Data: data})
+	if err != nil {
+		return err
+	}
+	existing := &corev1.Secret{}
+	err = r.Get(ctx, client.ObjectKey{Namespace: sr.Namespace, Name: credentialsSecretName(sr)}, existing)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && !metav1.IsControlledBy(existing, sr) {
+		return fmt.Errorf("secret %s exists and does not hold the credentials of this SecretRotation", existing.Name)
+	}
+
+	desired := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentialsSecretName(sr),
+			Namespace: sr.Namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{credentialsKey: payload},
+	}
+	if err := controllerutil.SetControllerReference(sr, desired, r.Scheme); err != nil {
+		return err
+	}
+	return applySecret(ctx, r.Client, desired)
+}
+
+// storedCredentials returns the current credentials of sr read back from the
+// credentials secret, or nil when they were not stored
+func (r *SecretRotationReconciler) storedCredentials(ctx context.Context, sr *secretsv1alpha1.SecretRotation) ([]sourceRead, error) {
+	id := credentialsID(sr)
+	if id == "" {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: sr.Namespace, Name: credentialsSecretName(sr)}, secret); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(secret, sr) {
+		return nil, fmt.Errorf("secret %s does not hold the credentials of this SecretRotation", secret.Name)
+	}
+
+	// Keep numbers as Vault returns them, so they encode the same way
+	var stored issuedCredentials
+	decoder := json.NewDecoder(bytes.NewReader(secret.Data[credentialsKey]))
+	decoder.UseNumber()
+	if err := decoder.Decode(&stored); err != nil {
+		return nil, fmt.Errorf("credentials in secret %s are corrupt: %w", secret.Name, err)
+	}
+	// Credentials stored for an earlier lease or certificate are of no use
+	if stored.ID != id {
+		return nil, nil
+	}
+
+	kvSecret := &vault.KVSecret{Data: stored.Data}
+	if lease := sr.Status.Lease; lease != nil {
+		kvSecret.Raw = &vault.Secret{
+			LeaseID:       lease.LeaseID,
+			LeaseDuration: lease.LeaseDuration,
+			Renewable:     lease.Renewable,
+			Data:          stored.Data,
+		}
+	}
+	return []sourceRead{{SecretSource: secretSources(&sr.Spec.SecretDataSpec)[0], secret: kvSecret}}, nil
+}
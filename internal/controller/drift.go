@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+const (
+	// targetSecretField indexes SecretRotations by spec.targetSecret
+	targetSecretField = ".spec.targetSecret"
+	// targetWorkloadField indexes SecretRotations by their target workloads
+	targetWorkloadField = ".spec.targetWorkloads"
+)
+
+// eventDriftRepaired is recorded when a target secret or workload was changed outside the operator
+const eventDriftRepaired = "DriftRepaired"
+
+// workloadIndexKey identifies a workload in the targetWorkloadField index
+func workloadIndexKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.ToLower(kind), namespace, name)
+}
+
+// targetWorkloadKeys returns the targetWorkloadField index values of sr,
+// including the workloads it discovered
+func targetWorkloadKeys(obj client.Object) []string {
+	sr := obj.(*secretsv1alpha1.SecretRotation)
+	workloads := slices.Concat(sr.Spec.TargetWorkloads, sr.Status.DiscoveredWorkloads)
+	keys := make([]string, 0, len(workloads))
+	for _, workload := range workloads {
+		namespace := workload.Namespace
+		if namespace == "" {
+			namespace = sr.Namespace
+		}
+		keys = append(keys, workloadIndexKey(workload.Kind, namespace, workload.Name))
+	}
+	return keys
+}
+
+// secretRotationsForSecret maps a Secret to the SecretRotations targeting it
+func (r *SecretRotationReconciler) secretRotationsForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	var secretRotations secretsv1alpha1.SecretRotationList
+	if err := r.List(ctx, &secretRotations, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{targetSecretField: obj.GetName()}); err != nil {
+		r.Log.Error(err, "failed to list SecretRotations", "secret", obj.GetName())
+		return nil
+	}
+	return secretRotationRequests(secretRotations.Items)
+}
+
+// secretRotationsForWorkload maps a workload to the SecretRotations
+// targeting it, in any namespace, and to those that may select or discover it
+func (r *SecretRotationReconciler) secretRotationsForWorkload(ctx context.Context, obj client.Object) []reconcile.Request {
+	kind := workloadKind(obj)
+	if kind == "" {
+		return nil
+	}
+
+	var secretRotations secretsv1alpha1.SecretRotationList
+	if err := r.List(ctx, &secretRotations,
+		client.MatchingFields{targetWorkloadField: workloadIndexKey(kind, obj.GetNamespace(), obj.GetName())}); err != nil {
+		r.Log.Error(err, "failed to list SecretRotations", "kind", kind, "name", obj.GetName())
+		return nil
+	}
+	requests := secretRotationRequests(secretRotations.Items)
+
+	var selecting secretsv1alpha1.SecretRotationList
+	if err := r.List(ctx, &selecting, client.MatchingFields{workloadSelectionField: "true"}); err != nil {
+		r.Log.Error(err, "failed to list SecretRotations", "kind", kind, "name", obj.GetName())
+		return requests
+	}
+	for _, sr := range selecting.Items {
+		if selectsWorkload(&sr, obj) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: sr.Namespace, Name: sr.Name},
+			})
+		}
+	}
+	return requests
+}
+
+func secretRotationRequests(secretRotations []secretsv1alpha1.SecretRotation) []reconcile.Request {
+	requests := make([]reconcile.Request, 0, len(secretRotations))
+	for _, sr := range secretRotations {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: sr.Namespace, Name: sr.Name},
+		})
+	}
+	return requests
+}
+
+// repairWorkloadDrift puts the checksum of the current secret back on the
+// workloads the operator restarted whose pod template lost it, which also
+// restarts them. It reports whether other workloads lack the checksum, such
+// as newly selected ones; those are left to restartWorkloads, so maintenance
+// windows and the rollout strategy apply to them.
+func (r *SecretRotationReconciler) repairWorkloadDrift(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation, workloads []secretsv1alpha1.WorkloadReference) bool {
+	checksum := sr.Status.SecretChecksum
+	// Workloads a halted rollout did not reach keep the previous checksum
+	if checksum == "" || sr.Status.WorkloadRestartPending || rolloutHalted(sr) {
+		return false
+	}
+	annotationPrefix := sr.Spec.AnnotationPrefix
+	if annotationPrefix == "" {
+		annotationPrefix = "secrets.github.com/"
+	}
+	annotationKey := annotationPrefix + "secret-checksum"
+
+	unrestarted := false
+	for _, workload := range workloads {
+		namespace := workload.Namespace
+		if namespace == "" {
+			namespace = sr.Namespace
+		}
+		obj, err := workloadObject(workload, namespace)
+		if err != nil {
+			continue
+		}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			continue
+		}
+		annotations, err := podTemplateAnnotations(obj, podTemplatePath(workload))
+		if err != nil || annotations[annotationKey] == checksum {
+			continue
+		}
+		if !slices.Contains(sr.Status.UpdatedWorkloads, workloadStatusKey(sr, workload)) {
+			unrestarted = true
+			continue
+		}
+
+		if _, err := r.updateWorkloadAnnotation(ctx, log, workload, sr.Namespace, annotationPrefix, checksum); err != nil {
+			log.Error(err, "failed to repair workload annotation", "kind", workload.Kind, "name", workload.Name)
+			continue
+		}
+		log.Info("Repaired workload annotation", "kind", workload.Kind, "name", workload.Name, "checksum", checksum)
+		driftRepairs.WithLabelValues(workload.Kind).Inc()
+		r.Recorder.Eventf(sr, corev1.EventTypeNormal, eventDriftRepaired,
+			"Restored checksum annotation on %s %s/%s", workload.Kind, namespace, workload.Name)
+		r.Recorder.Eventf(obj, corev1.EventTypeNormal, eventDriftRepaired,
+			"Restarting pods: checksum annotation for secret %s/%s was removed or changed (SecretRotation %s)",
+			sr.Namespace, sr.Spec.TargetSecret, sr.Name)
+	}
+	return unrestarted
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+var _ = Describe("repairWorkloadDrift", func() {
+	const annotationKey = "secrets.github.com/secret-checksum"
+	ctx := context.Background()
+
+	It("only puts back annotations on workloads the operator restarted", func() {
+		restarted := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "restarted", Namespace: "default"}}
+		selected := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "selected", Namespace: "default"}}
+		r := &SecretRotationReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(restarted, selected).Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+		sr := &secretsv1alpha1.SecretRotation{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+		sr.Status.SecretChecksum = "abc"
+		sr.Status.UpdatedWorkloads = []string{"Deployment/restarted"}
+		workloads := []secretsv1alpha1.WorkloadReference{
+			{Kind: "Deployment", Name: "restarted"},
+			{Kind: "Deployment", Name: "selected"},
+		}
+
+		Expect(r.repairWorkloadDrift(ctx, logr.Discard(), sr, workloads)).To(BeTrue())
+
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(restarted), restarted)).To(Succeed())
+		Expect(restarted.Spec.Template.Annotations).To(HaveKeyWithValue(annotationKey, "abc"))
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(selected), selected)).To(Succeed())
+		Expect(selected.Spec.Template.Annotations).NotTo(HaveKey(annotationKey))
+	})
+})
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+// Event reasons recorded by the SecretRotation controller. Failures are
+// recorded with the reason of the condition they are reported in.
+const (
+	eventSecretCreated           = "SecretCreated"
+	eventSecretUpdated           = "SecretUpdated"
+	eventSecretUpToDate          = "SecretUpToDate"
+	eventRotated                 = "Rotated"
+	eventWorkloadRestarted       = "WorkloadRestarted"
+	eventWorkloadRestartFailed   = "WorkloadRestartFailed"
+	eventWorkloadRestartDeferred = "WorkloadRestartDeferred"
+)
+
+// noOpEventInterval limits SecretUpToDate events to one per SecretRotation per interval
+const noOpEventInterval = time.Hour
+
+// noOpSyncDue reports whether a SecretUpToDate event may be recorded for sr
+// and, if so, remembers that it was
+func (r *SecretRotationReconciler) noOpSyncDue(sr *secretsv1alpha1.SecretRotation) bool {
+	key := types.NamespacedName{Namespace: sr.Namespace, Name: sr.Name}
+	r.noOpEventsMu.Lock()
+	defer r.noOpEventsMu.Unlock()
+	if last, ok := r.noOpEvents[key]; ok && time.Since(last) < noOpEventInterval {
+		return false
+	}
+	if r.noOpEvents == nil {
+		r.noOpEvents = make(map[types.NamespacedName]time.Time)
+	}
+	r.noOpEvents[key] = time.Now()
+	return true
+}
+
+// forgetNoOpSync drops the rate limiting state of a deleted SecretRotation
+func (r *SecretRotationReconciler) forgetNoOpSync(key types.NamespacedName) {
+	r.noOpEventsMu.Lock()
+	defer r.noOpEventsMu.Unlock()
+	delete(r.noOpEvents, key)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/secretdata"
+)
+
+// mapKeys applies mapping to the Vault data and returns it keyed by the
+// target secret keys. It fails on invalid patterns, on two Vault keys mapped
+// to the same secret key and on secret keys Kubernetes does not accept.
+func mapKeys(data map[string]interface{}, mapping *secretsv1alpha1.KeyMapping) (map[string]interface{}, error) {
+	if mapping == nil {
+		return data, nil
+	}
+	for _, pattern := range append(append([]string{}, mapping.Include...), mapping.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+		}
+	}
+
+	// Walk the keys in order so conflicts are reported deterministically
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	mapped := make(map[string]interface{}, len(data))
+	sources := make(map[string]string, len(data))
+	for _, k := range keys {
+		if len(mapping.Include) > 0 && !secretdata.MatchKey(mapping.Include, k) {
+			continue
+		}
+		if secretdata.MatchKey(mapping.Exclude, k) {
+			continue
+		}
+
+		name := k
+		if renamed, ok := mapping.Rename[k]; ok {
+			name = renamed
+		}
+		name = mapping.Prefix + name
+		if errs := validation.IsConfigMapKey(name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid secret key %q for Vault key %q: %s", name, k, strings.Join(errs, ", "))
+		}
+		if other, ok := sources[name]; ok {
+			return nil, fmt.Errorf("vault keys %q and %q both map to secret key %q", other, k, name)
+		}
+		sources[name] = k
+		mapped[name] = data[k]
+	}
+	return mapped, nil
+}
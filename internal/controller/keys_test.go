@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+var _ = Describe("mapKeys", func() {
+	data := map[string]interface{}{
+		"username":      "app",
+		"password":      "s3cr3t",
+		"db_host":       "db.example.com",
+		"db_port":       "5432",
+		"internal_note": "rotate monthly",
+	}
+
+	DescribeTable("maps the Vault keys to secret keys",
+		func(mapping *secretsv1alpha1.KeyMapping, expected map[string]interface{}) {
+			mapped, err := mapKeys(data, mapping)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mapped).To(Equal(expected))
+		},
+		Entry("without a mapping", nil, data),
+		Entry("with include patterns",
+			&secretsv1alpha1.KeyMapping{Include: []string{"db_*", "username"}},
+			map[string]interface{}{"db_host": "db.example.com", "db_port": "5432", "username": "app"}),
+		Entry("with exclude patterns",
+			&secretsv1alpha1.KeyMapping{Exclude: []string{"internal_*", "db_port"}},
+			map[string]interface{}{"username": "app", "password": "s3cr3t", "db_host": "db.example.com"}),
+		Entry("with renames",
+			&secretsv1alpha1.KeyMapping{Rename: map[string]string{"username": "DB_USER", "password": "DB_PASSWORD"}},
+			map[string]interface{}{
+				"DB_USER": "app", "DB_PASSWORD": "s3cr3t",
+				"db_host": "db.example.com", "db_port": "5432", "internal_note": "rotate monthly",
+			}),
+		Entry("with a prefix",
+			&secretsv1alpha1.KeyMapping{Prefix: "app."},
+			map[string]interface{}{
+				"app.username": "app", "app.password": "s3cr3t",
+				"app.db_host": "db.example.com", "app.db_port": "5432", "app.internal_note": "rotate monthly",
+			}),
+		Entry("excluding keys the include patterns select",
+			&secretsv1alpha1.KeyMapping{Include: []string{"db_*"}, Exclude: []string{"db_port"}},
+			map[string]interface{}{"db_host": "db.example.com"}),
+		Entry("renaming included keys and prefixing the new names",
+			&secretsv1alpha1.KeyMapping{
+				Include: []string{"username", "password"},
+				Rename:  map[string]string{"username": "user"},
+				Prefix:  "DB_",
+			},
+			map[string]interface{}{"DB_user": "app", "DB_password": "s3cr3t"}),
+		Entry("ignoring renames of excluded keys",
+			&secretsv1alpha1.KeyMapping{
+				Exclude: []string{"internal_note", "db_*"},
+				Rename:  map[string]string{"internal_note": "password"},
+			},
+			map[string]interface{}{"username": "app", "password": "s3cr3t"}),
+		Entry("swapping two keys",
+			&secretsv1alpha1.KeyMapping{
+				Include: []string{"username", "password"},
+				Rename:  map[string]string{"username": "password", "password": "username"},
+			},
+			map[string]interface{}{"password": "app", "username": "s3cr3t"}),
+	)
+
+	DescribeTable("rejects invalid mappings",
+		func(mapping *secretsv1alpha1.KeyMapping, message string) {
+			_, err := mapKeys(data, mapping)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("with an invalid include pattern",
+			&secretsv1alpha1.KeyMapping{Include: []string{"db_["}}, `invalid key pattern "db_["`),
+		Entry("with an invalid exclude pattern",
+			&secretsv1alpha1.KeyMapping{Exclude: []string{"["}}, `invalid key pattern "["`),
+		Entry("when a rename collides with another key",
+			&secretsv1alpha1.KeyMapping{Rename: map[string]string{"db_host": "username"}},
+			`vault keys "db_host" and "username" both map to secret key "username"`),
+		Entry("when two keys are renamed to the same key",
+			&secretsv1alpha1.KeyMapping{Rename: map[string]string{"db_host": "host", "db_port": "host"}},
+			`vault keys "db_host" and "db_port" both map to secret key "host"`),
+		Entry("when a renamed key collides with another key after the prefix",
+			&secretsv1alpha1.KeyMapping{Prefix: "app_", Rename: map[string]string{"db_host": "db_port"}},
+			`vault keys "db_host" and "db_port" both map to secret key "app_db_port"`),
+		Entry("when a renamed key is not a valid secret key",
+			&secretsv1alpha1.KeyMapping{Rename: map[string]string{"username": "db user"}},
+			`invalid secret key "db user" for Vault key "username"`),
+		Entry("when a prefix makes the keys invalid",
+			&secretsv1alpha1.KeyMapping{Prefix: "app/"},
+			`invalid secret key "app/db_host" for Vault key "db_host"`),
+	)
+})
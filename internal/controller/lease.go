@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	vault "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/vaultclient"
+)
+
+// reconcileLease keeps the dynamic credentials of sr alive. It returns true
+// while the current credentials stay in use, renewing their lease when it is
+// due, and false when new credentials have to be requested.
+func (r *SecretRotationReconciler) reconcileLease(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation, vaultClient *vault.Client) (bool, error) {
+	lease := sr.Status.Lease
+	credsPath := vaultclient.DatabaseCredentialsPath(sr.Spec.Mount, sr.Spec.Role)
+	if lease == nil || !strings.HasPrefix(lease.LeaseID, credsPath+"/") {
+		return false, nil
+	}
+
+	now := time.Now()
+	if now.Before(leaseActionTime(sr)) {
+		return true, nil
+	}
+	if !lease.Renewable || lease.MaxTTLReached {
+		log.Info("Lease cannot be extended, requesting new credentials", "leaseID", lease.LeaseID)
+		return false, nil
+	}
+
+	renewal, err := vaultclient.RenewLease(ctx, vaultClient, lease.LeaseID, lease.LeaseDuration)
+	if err != nil {
+		log.Error(err, "failed to renew lease, requesting new credentials", "leaseID", lease.LeaseID)
+		return false, nil
+	}
+	renewedAt := metav1.NewTime(now)
+	lease.LastRenewalTime = &renewedAt
+	lease.ExpireTime = metav1.NewTime(now.Add(time.Duration(renewal.LeaseDuration) * time.Second))
+	lease.Renewable = renewal.Renewable
+	// Vault grants less than requested once the lease runs into its max TTL
+	lease.MaxTTLReached = renewal.LeaseDuration < lease.LeaseDuration
+	log.Info("Renewed lease", "leaseID", lease.LeaseID, "expireTime", lease.ExpireTime, "maxTTLReached", lease.MaxTTLReached)
+
+	if !now.Before(leaseActionTime(sr)) {
+		// Too close to the max TTL to keep going, replace the credentials now
+		return false, nil
+	}
+	return true, nil
+}
+
+// revokeLease revokes leaseID. Failures are only logged, since the lease
+// still expires at the end of its TTL.
+func (r *SecretRotationReconciler) revokeLease(ctx context.Context, log logr.Logger, vaultClient *vault.Client, leaseID string) {
+	if err := vaultclient.RevokeLease(ctx, vaultClient, leaseID); err != nil {
+		log.Error(err, "failed to revoke lease", "leaseID", leaseID)
+		return
+	}
+	log.Info("Revoked lease", "leaseID", leaseID)
+}
+
+// newLease builds the lease status for freshly issued credentials
+func newLease(secret *vault.Secret) *secretsv1alpha1.VaultLease {
+	if secret == nil || secret.LeaseID == "" {
+		return nil
+	}
+	now := time.Now()
+	return &secretsv1alpha1.VaultLease{
+		LeaseID:       secret.LeaseID,
+		LeaseDuration: secret.LeaseDuration,
+		Renewable:     secret.Renewable,
+		IssueTime:     metav1.NewTime(now),
+		ExpireTime:    metav1.NewTime(now.Add(time.Duration(secret.LeaseDuration) * time.Second)),
+	}
+}
+
+// leaseActionTime returns when the lease of sr next has to be renewed or
+// replaced. A renewBefore that is not shorter than the lease duration would
+// be due right after issuing, so a third of the lease duration is used then.
+func leaseActionTime(sr *secretsv1alpha1.SecretRotation) time.Time {
+	lease := sr.Status.Lease
+	duration := time.Duration(lease.LeaseDuration) * time.Second
+	renewBefore := duration / 3
+	if sr.Spec.RenewBefore != nil && sr.Spec.RenewBefore.Duration < duration {
+		renewBefore = sr.Spec.RenewBefore.Duration
+	}
+	return lease.ExpireTime.Add(-renewBefore)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+var _ = Describe("leaseActionTime", func() {
+	expireTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	DescribeTable("renews renewBefore ahead of the expiry",
+		func(renewBefore *metav1.Duration, expected time.Duration) {
+			sr := &secretsv1alpha1.SecretRotation{}
+			sr.Spec.RenewBefore = renewBefore
+			sr.Status.Lease = &secretsv1alpha1.VaultLease{LeaseDuration: 3600, ExpireTime: metav1.NewTime(expireTime)}
+			Expect(leaseActionTime(sr)).To(Equal(expireTime.Add(-expected)))
+		},
+		Entry("by default a third of the lease duration", nil, 20*time.Minute),
+		Entry("with renewBefore", &metav1.Duration{Duration: 5 * time.Minute}, 5*time.Minute),
+		Entry("with a renewBefore as long as the lease", &metav1.Duration{Duration: time.Hour}, 20*time.Minute),
+		Entry("with a renewBefore longer than the lease", &metav1.Duration{Duration: 24 * time.Hour}, 20*time.Minute),
+	)
+})
@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+var (
+	vaultReadDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "secret_rotator_vault_read_duration_seconds",
+		Help:    "Latency of reading secrets from Vault.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"mount", "path"})
+	vaultReadErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "secret_rotator_vault_read_errors_total",
+		Help: "Failed reads of secrets from Vault.",
+	}, []string{"mount", "path", "reason"})
+	secretUpdates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "secret_rotator_secret_updates_total",
+		Help: "Target secrets created or updated with new data from Vault, or rolled back to previous data.",
+	}, []string{"namespace", "secretrotation", "operation"})
+	workloadRollouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "secret_rotator_workload_rollouts_total",
+		Help: "Workload restarts triggered by a changed secret.",
+	}, []string{"kind"})
+	workloadRolloutFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "secret_rotator_workload_rollout_failures_total",
+		Help: "Workload restarts that could not be triggered.",
+	}, []string{"kind"})
+	driftRepairs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "secret_rotator_drift_repairs_total",
+		Help: "Target secrets and workloads changed outside the operator and put back.",
+	}, []string{"kind"})
+	lastSyncAge = newTimeCollector(
+		"secret_rotator_seconds_since_last_sync",
+		"Seconds since the target secret was last successfully synced from Vault, or since the SecretRotation was created if it never was.",
+		true, "namespace", "secretrotation")
+	credentialsExpiry = newTimeCollector(
+		"secret_rotator_credentials_expiry_seconds",
+		"Seconds until the lease or certificate in the target secret expires.",
+		false, "namespace", "secretrotation", "type")
+	credentialsRenewal = newTimeCollector(
+		"secret_rotator_credentials_renewal_seconds",
+		"Seconds until the lease or certificate in the target secret is due to be renewed or replaced, negative once overdue.",
+		false, "namespace", "secretrotation", "type")
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		vaultReadDuration,
+		vaultReadErrors,
+		secretUpdates,
+		workloadRollouts,
+		workloadRolloutFailures,
+		driftRepairs,
+		lastSyncAge,
+		credentialsExpiry,
+		credentialsRenewal,
+	)
+}
+
+// recordTimeMetrics updates the sync age and expiry metrics of sr from its status
+func recordTimeMetrics(sr *secretsv1alpha1.SecretRotation) {
+	// A SecretRotation that never synced is as stale as it is old
+	lastSync := sr.CreationTimestamp.Time
+	if sr.Status.LastSyncTime != nil {
+		lastSync = sr.Status.LastSyncTime.Time
+	}
+	lastSyncAge.set(lastSync, sr.Namespace, sr.Name)
+	if lease := sr.Status.Lease; lease != nil {
+		credentialsExpiry.set(lease.ExpireTime.Time, sr.Namespace, sr.Name, "lease")
+		credentialsRenewal.set(leaseActionTime(sr), sr.Namespace, sr.Name, "lease")
+	} else {
+		credentialsExpiry.delete(sr.Namespace, sr.Name, "lease")
+		credentialsRenewal.delete(sr.Namespace, sr.Name, "lease")
+	}
+	if cert := sr.Status.Certificate; cert != nil {
+		credentialsExpiry.set(cert.NotAfter.Time, sr.Namespace, sr.Name, "certificate")
+		credentialsRenewal.set(cert.RenewalTime.Time, sr.Namespace, sr.Name, "certificate")
+	} else {
+		credentialsExpiry.delete(sr.Namespace, sr.Name, "certificate")
+		credentialsRenewal.delete(sr.Namespace, sr.Name, "certificate")
+	}
+}
+
+// forgetMetrics drops the per-SecretRotation series of a deleted SecretRotation
+func forgetMetrics(key types.NamespacedName) {
+	labels := prometheus.Labels{"namespace": key.Namespace, "secretrotation": key.Name}
+	secretUpdates.DeletePartialMatch(labels)
+	lastSyncAge.delete(key.Namespace, key.Name)
+	credentialsExpiry.delete(key.Namespace, key.Name, "lease")
+	credentialsExpiry.delete(key.Namespace, key.Name, "certificate")
+	credentialsRenewal.delete(key.Namespace, key.Name, "lease")
+	credentialsRenewal.delete(key.Namespace, key.Name, "certificate")
+}
+
+// timeCollector reports the seconds between the time of the scrape and a
+// recorded point in time, so the value keeps moving between reconciles
+type timeCollector struct {
+	desc *prometheus.Desc
+	// since reports the seconds since the recorded time instead of until it
+	since bool
+
+	mu    sync.Mutex
+	times map[string]time.Time
+}
+
+func newTimeCollector(name, help string, since bool, labels ...string) *timeCollector {
+	return &timeCollector{
+		desc:  prometheus.NewDesc(name, help, labels, nil),
+		since: since,
+		times: make(map[string]time.Time),
+	}
+}
+
+func (c *timeCollector) set(t time.Time, labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.times[labelKey(labels)] = t
+}
+
+func (c *timeCollector) delete(labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.times, labelKey(labels))
+}
+
+// Describe implements prometheus.Collector
+func (c *timeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *timeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for key, t := range c.times {
+		value := t.Sub(now).Seconds()
+		if c.since {
+			value = -value
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, value, strings.Split(key, labelSeparator)...)
+	}
+}
+
+// labelSeparator joins label values into map keys; it cannot occur in Kubernetes names
+const labelSeparator = "\x00"
+
+func labelKey(labels []string) string {
+	return strings.Join(labels, labelSeparator)
+}
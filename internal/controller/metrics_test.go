@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+// collectValues returns the values of the metrics c collects keyed by their
+// label values joined with "/"
+func collectValues(c prometheus.Collector) map[string]float64 {
+	ch := make(chan prometheus.Metric, 10)
+	c.Collect(ch)
+	close(ch)
+	values := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		Expect(metric.Write(&m)).To(Succeed())
+		labels := make([]string, 0, len(m.GetLabel()))
+		for _, label := range m.GetLabel() {
+			labels = append(labels, label.GetValue())
+		}
+		values[strings.Join(labels, "/")] = m.GetGauge().GetValue()
+	}
+	return values
+}
+
+var _ = Describe("timeCollector", func() {
+	It("reports the seconds since or until the recorded time at scrape time", func() {
+		since := newTimeCollector("since", "help", true, "name")
+		since.set(time.Now().Add(-time.Hour), "a")
+		until := newTimeCollector("until", "help", false, "name")
+		until.set(time.Now().Add(time.Hour), "a")
+
+		Expect(collectValues(since)).To(HaveKeyWithValue("a", BeNumerically("~", 3600, 5)))
+		Expect(collectValues(until)).To(HaveKeyWithValue("a", BeNumerically("~", 3600, 5)))
+	})
+
+	It("drops deleted series", func() {
+		c := newTimeCollector("since", "help", true, "namespace", "secretrotation")
+		c.set(time.Now(), "default", "a")
+		c.set(time.Now(), "default", "b")
+		c.delete("default", "a")
+		Expect(collectValues(c)).To(HaveLen(1))
+		Expect(collectValues(c)).To(HaveKey("default/b"))
+	})
+})
+
+var _ = Describe("recordTimeMetrics", func() {
+	var sr *secretsv1alpha1.SecretRotation
+
+	BeforeEach(func() {
+		sr = &secretsv1alpha1.SecretRotation{ObjectMeta: metav1.ObjectMeta{
+			Name:              "metrics",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+		}}
+		DeferCleanup(forgetMetrics, types.NamespacedName{Namespace: "default", Name: "metrics"})
+	})
+
+	It("counts the sync age from creation until the first sync", func() {
+		recordTimeMetrics(sr)
+		Expect(collectValues(lastSyncAge)).To(HaveKeyWithValue("default/metrics", BeNumerically("~", 7200, 5)))
+
+		sr.Status.LastSyncTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		recordTimeMetrics(sr)
+		Expect(collectValues(lastSyncAge)).To(HaveKeyWithValue("default/metrics", BeNumerically("~", 60, 5)))
+	})
+
+	It("reports the expiry of the current lease or certificate only", func() {
+		sr.Status.Lease = &secretsv1alpha1.VaultLease{ExpireTime: metav1.NewTime(time.Now().Add(time.Hour))}
+		recordTimeMetrics(sr)
+		Expect(collectValues(credentialsExpiry)).To(HaveKeyWithValue("default/metrics/lease", BeNumerically("~", 3600, 5)))
+
+		sr.Status.Lease = nil
+		sr.Status.Certificate = &secretsv1alpha1.IssuedCertificate{NotAfter: metav1.NewTime(time.Now().Add(time.Hour))}
+		recordTimeMetrics(sr)
+		values := collectValues(credentialsExpiry)
+		Expect(values).NotTo(HaveKey("default/metrics/lease"))
+		Expect(values).To(HaveKeyWithValue("default/metrics/certificate", BeNumerically("~", 3600, 5)))
+	})
+
+	It("reports when the lease or certificate is due for renewal", func() {
+		sr.Status.Lease = &secretsv1alpha1.VaultLease{
+			LeaseDuration: 3600,
+			ExpireTime:    metav1.NewTime(time.Now().Add(10 * time.Minute)),
+		}
+		recordTimeMetrics(sr)
+		// A third of the lease duration before expiry, so ten minutes overdue
+		Expect(collectValues(credentialsRenewal)).To(HaveKeyWithValue("default/metrics/lease", BeNumerically("~", -600, 5)))
+
+		sr.Status.Lease = nil
+		sr.Status.Certificate = &secretsv1alpha1.IssuedCertificate{
+			NotAfter:    metav1.NewTime(time.Now().Add(time.Hour)),
+			RenewalTime: metav1.NewTime(time.Now().Add(20 * time.Minute)),
+		}
+		recordTimeMetrics(sr)
+		values := collectValues(credentialsRenewal)
+		Expect(values).NotTo(HaveKey("default/metrics/lease"))
+		Expect(values).To(HaveKeyWithValue("default/metrics/certificate", BeNumerically("~", 1200, 5)))
+	})
+
+	It("forgets the series of deleted SecretRotations", func() {
+		sr.Status.Lease = &secretsv1alpha1.VaultLease{ExpireTime: metav1.NewTime(time.Now().Add(time.Hour))}
+		recordTimeMetrics(sr)
+		forgetMetrics(types.NamespacedName{Namespace: "default", Name: "metrics"})
+		Expect(collectValues(lastSyncAge)).NotTo(HaveKey("default/metrics"))
+		Expect(collectValues(credentialsExpiry)).NotTo(HaveKey("default/metrics/lease"))
+		Expect(collectValues(credentialsRenewal)).NotTo(HaveKey("default/metrics/lease"))
+	})
+})
@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+// Event reasons recorded when the deletion policy is applied
+const (
+	eventSecretDeleted  = "TargetSecretDeleted"
+	eventSecretRetained = "SecretRetained"
+	eventSecretOrphaned = "SecretOrphaned"
+	eventSecretAdopted  = "SecretAdopted"
+)
+
+// secretOwnedBy reports whether secret is managed by sr
+func (r *SecretRotationReconciler) secretOwnedBy(secret *corev1.Secret, sr *secretsv1alpha1.SecretRotation) bool {
+	if owner := metav1.GetControllerOf(secret); owner != nil {
+		return owner.UID == sr.UID
+	}
+	if name, ok := secret.Labels[secretsv1alpha1.ManagedByLabel]; ok {
+		return name == sr.Name
+	}
+	// Secrets written before ownership was tracked carry neither; they are
+	// ours if they still hold the data that was last synced
+	return sr.Status.SecretChecksum != "" && calculateSecretChecksum(secret.Data) == sr.Status.SecretChecksum
+}
+
+// claimSecret returns an error, and the condition reason to report it with,
+// unless sr may manage the existing secret
+func (r *SecretRotationReconciler) claimSecret(secret *corev1.Secret, sr *secretsv1alpha1.SecretRotation) (string, error) {
+	if r.secretOwnedBy(secret, sr) {
+		return "", nil
+	}
+	if owner := metav1.GetControllerOf(secret); owner != nil {
+		return secretsv1alpha1.ReasonSecretOwnerConflict,
+			fmt.Errorf("secret %s is controlled by %s %s", secret.Name, owner.Kind, owner.Name)
+	}
+	if !sr.Spec.Adopt {
+		return secretsv1alpha1.ReasonSecretNotOwned,
+			fmt.Errorf("secret %s already exists and is not managed by this SecretRotation; set adopt to take it over", secret.Name)
+	}
+	return "", nil
+}
+
+// setSecretOwner marks secret as managed by sr and reports whether it changed
+func (r *SecretRotationReconciler) setSecretOwner(secret *corev1.Secret, sr *secretsv1alpha1.SecretRotation) (bool, error) {
+	changed := false
+	if secret.Labels[secretsv1alpha1.ManagedByLabel] != sr.Name {
+		if secret.Labels == nil {
+			secret.Labels = make(map[string]string)
+		}
+		secret.Labels[secretsv1alpha1.ManagedByLabel] = sr.Name
+		changed = true
+	}
+	if metav1.GetControllerOf(secret) == nil {
+		if err := controllerutil.SetControllerReference(sr, secret, r.Scheme); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// finalize applies the deletion policy of sr to its target secret and
+// removes the finalizer
+func (r *SecretRotationReconciler) finalize(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation) error {
+	if !controllerutil.ContainsFinalizer(sr, secretsv1alpha1.SecretRotationFinalizer) {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: sr.Namespace, Name: sr.Spec.TargetSecret}, secret)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && r.secretOwnedBy(secret, sr) {
+		policy := sr.Spec.DeletionPolicy
+		if policy == "" {
+			policy = secretsv1alpha1.DeletionPolicyRetain
+		}
+		switch policy {
+		case secretsv1alpha1.DeletionPolicyDelete:
+			if err := r.Delete(ctx, secret); err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+			log.Info("Deleted Kubernetes Secret", "secret", secret.Name)
+			r.Recorder.Eventf(sr, corev1.EventTypeNormal, eventSecretDeleted, "Deleted secret %s", secret.Name)
+		default:
+			// Drop the owner reference, or the garbage collector deletes the secret anyway
+			reason, dropLabel := eventSecretRetained, ""
+			if policy == secretsv1alpha1.DeletionPolicyOrphan {
+				reason, dropLabel = eventSecretOrphaned, secretsv1alpha1.ManagedByLabel
+			}
+			if err := releaseSecret(ctx, r.Client, secret, sr.UID, dropLabel); err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+			log.Info("Released Kubernetes Secret", "secret", secret.Name, "policy", policy)
+			r.Recorder.Eventf(sr, corev1.EventTypeNormal, reason, "Kept secret %s (deletion policy %s)", secret.Name, policy)
+		}
+	}
+
+	// Nothing renews the dynamic credentials any more, so do not leave them valid until their TTL
+	if lease := sr.Status.Lease; lease != nil {
+		if vaultClient, err := r.vaultClientFor(ctx, sr); err != nil {
+			log.Error(err, "not authenticated to Vault, leaving the lease to expire", "leaseID", lease.LeaseID)
+		} else {
+			r.revokeLease(ctx, log, vaultClient, lease.LeaseID)
+		}
+	}
+	r.releaseVaultClient(ctx, log, sr)
+
+	key := types.NamespacedName{Namespace: sr.Namespace, Name: sr.Name}
+	r.forgetNoOpSync(key)
+	forgetMetrics(key)
+	controllerutil.RemoveFinalizer(sr, secretsv1alpha1.SecretRotationFinalizer)
+	return r.Update(ctx, sr)
+}
+
+// removeOwner returns refs without references to the owner with uid
+func removeOwner(refs []metav1.OwnerReference, uid types.UID) []metav1.OwnerReference {
+	var kept []metav1.OwnerReference
+	for _, ref := range refs {
+		if ref.UID != uid {
+			kept = append(kept, ref)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+// defaultRollbackHistoryLimit is how many payloads are kept to roll back to
+const defaultRollbackHistoryLimit = 3
+
+// historySecretName returns the name of the secret holding the payloads sr
+// can roll back to
+func historySecretName(sr *secretsv1alpha1.SecretRotation) string {
+	return sr.Name + "-rollback-history"
+}
+
+// historySecret returns the history secret of sr, or a new one when it does
+// not exist yet
+func (r *SecretRotationReconciler) historySecret(ctx context.Context, sr *secretsv1alpha1.SecretRotation) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: sr.Namespace, Name: historySecretName(sr)}, secret)
+	if kerrors.IsNotFound(err) {
+		return &corev1.Secret{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !metav1.IsControlledBy(secret, sr) {
+		return nil, fmt.Errorf("secret %s exists and is not the rollback history of this SecretRotation", secret.Name)
+	}
+	return secret, nil
+}
+
+// recordPayload adds data, the payload with checksum read from version of
+// the Vault secret, to the rollback history of sr and drops the oldest
+// payloads beyond the history limit
+func (r *SecretRotationReconciler) recordPayload(ctx context.Context, sr *secretsv1alpha1.SecretRotation, checksum string, data map[string][]byte, version int) error {
+	status := sr.Status.Rollback
+	if status == nil {
+		status = &secretsv1alpha1.RollbackStatus{HistorySecret: historySecretName(sr)}
+		sr.Status.Rollback = status
+	}
+	if len(status.History) > 0 && status.History[0].Checksum == checksum {
+		return nil
+	}
+
+	existing, err := r.historySecret(ctx, sr)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	limit := int(sr.Spec.Rollback.HistoryLimit)
+	if limit <= 0 {
+		limit = defaultRollbackHistoryLimit
+	}
+	history := []secretsv1alpha1.PayloadRecord{{Checksum: checksum, SyncTime: metav1.Now(), Version: version}}
+	payloads := map[string][]byte{checksum: payload}
+	for _, record := range status.History {
+		if len(history) == limit {
+			break
+		}
+		// Payloads lost from the history secret cannot be rolled back to
+		if record.Checksum == checksum || existing.Data[record.Checksum] == nil {
+			continue
+		}
+		history = append(history, record)
+		payloads[record.Checksum] = existing.Data[record.Checksum]
+	}
+
+	desired := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      historySecretName(sr),
+			Namespace: sr.Namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: payloads,
+	}
+	if err := controllerutil.SetControllerReference(sr, desired, r.Scheme); err != nil {
+		return err
+	}
+	if err := applySecret(ctx, r.Client, desired); err != nil {
+		return err
+	}
+	status.History = history
+	return nil
+}
+
+// loadPayload returns the payload with checksum from the rollback history of sr
+func (r *SecretRotationReconciler) loadPayload(ctx context.Context, sr *secretsv1alpha1.SecretRotation, checksum string) (map[string][]byte, error) {
+	secret, err := r.historySecret(ctx, sr)
+	if err != nil {
+		return nil, err
+	}
+	payload, ok := secret.Data[checksum]
+	if !ok {
+		return nil, fmt.Errorf("secret data %s is missing from secret %s", checksum, historySecretName(sr))
+	}
+	var data map[string][]byte
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, fmt.Errorf("secret data %s in secret %s is corrupt: %w", checksum, historySecretName(sr), err)
+	}
+	return data, nil
+}
+
+// keepRolledBack returns the data to write to the target secret of sr and
+// whether it is the data restored by a rollback: Vault data that was rolled
+// back from is replaced by the restored data until Vault holds different data
+func (r *SecretRotationReconciler) keepRolledBack(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation, data map[string][]byte) (map[string][]byte, bool) {
+	if sr.Spec.Rollback == nil {
+		sr.Status.Rollback = nil
+		meta.RemoveStatusCondition(&sr.Status.Conditions, secretsv1alpha1.ConditionRolledBack)
+		return data, false
+	}
+	status := sr.Status.Rollback
+	if status == nil || status.RejectedChecksum == "" {
+		return data, false
+	}
+
+	if calculateSecretChecksum(data) == status.RejectedChecksum {
+		restored, err := r.loadPayload(ctx, sr, sr.Status.SecretChecksum)
+		if err == nil {
+			return restored, true
+		}
+		log.Error(err, "failed to load the rolled back secret data, syncing the data in Vault")
+	} else {
+		log.Info("Secret data in Vault changed since the rollback", "rejected", status.RejectedChecksum)
+	}
+	status.RejectedChecksum = ""
+	status.RejectedVersion = 0
+	meta.RemoveStatusCondition(&sr.Status.Conditions, secretsv1alpha1.ConditionRolledBack)
+	return data, false
+}
+
+// rollback restores the previous payload of sr to its target secret after
+// the current one failed to roll out, and restarts workloads again
+func (r *SecretRotationReconciler) rollback(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation, workloads []secretsv1alpha1.WorkloadReference, failure string) {
+	rejected := sr.Status.Rollout.Checksum
+	status := sr.Status.Rollback
+	if status == nil {
+		status = &secretsv1alpha1.RollbackStatus{HistorySecret: historySecretName(sr)}
+		sr.Status.Rollback = status
+	}
+
+	var previous *secretsv1alpha1.PayloadRecord
+	for i := range status.History {
+		if status.History[i].Checksum != rejected {
+			previous = &status.History[i]
+			break
+		}
+	}
+	err := fmt.Errorf("no previous secret data to roll back to")
+	var data map[string][]byte
+	if previous != nil {
+		data, err = r.loadPayload(ctx, sr, previous.Checksum)
+	}
+	if err == nil {
+		err = r.writeTargetSecret(ctx, sr, data)
+	}
+	if err != nil {
+		log.Error(err, "failed to roll back secret data", "checksum", rejected)
+		message := fmt.Sprintf("Failed to roll back secret %s: %v", sr.Spec.TargetSecret, err)
+		r.Recorder.Event(sr, corev1.EventTypeWarning, secretsv1alpha1.ReasonRollbackFailed, message)
+		meta.SetStatusCondition(&sr.Status.Conditions, metav1.Condition{
+			Type:               secretsv1alpha1.ConditionRolledBack,
+			Status:             metav1.ConditionFalse,
+			Reason:             secretsv1alpha1.ReasonRollbackFailed,
+			Message:            message,
+			ObservedGeneration: sr.Generation,
+		})
+		return
+	}
+
+	// Never roll forward to the rejected data again
+	checksum := previous.Checksum
+	status.RejectedChecksum = rejected
+	status.RejectedVersion = 0
+	history := make([]secretsv1alpha1.PayloadRecord, 0, len(status.History))
+	for _, record := range status.History {
+		if record.Checksum == rejected {
+			status.RejectedVersion = record.Version
+			continue
+		}
+		history = append(history, record)
+	}
+	status.History = history
+	now := metav1.NewTime(time.Now())
+	status.LastRollbackTime = &now
+	sr.Status.SecretChecksum = checksum
+	sr.Status.LastChangeTime = &now
+
+	message := fmt.Sprintf("Restored secret %s to data %s after data %s failed to roll out: %s",
+		sr.Spec.TargetSecret, checksum, rejected, failure)
+	log.Info("Rolled back secret data", "from", rejected, "to", checksum)
+	secretUpdates.WithLabelValues(sr.Namespace, sr.Name, "rollback").Inc()
+	r.Recorder.Event(sr, corev1.EventTypeWarning, secretsv1alpha1.ReasonRolledBack, message)
+	meta.SetStatusCondition(&sr.Status.Conditions, metav1.Condition{
+		Type:               secretsv1alpha1.ConditionRolledBack,
+		Status:             metav1.ConditionTrue,
+		Reason:             secretsv1alpha1.ReasonRolledBack,
+		Message:            message,
+		ObservedGeneration: sr.Generation,
+	})
+
+	// Restart the workloads again so they pick up the restored data
+	sr.Status.Rollout = &secretsv1alpha1.RolloutStatus{
+		Checksum:    checksum,
+		Phase:       secretsv1alpha1.RolloutPhaseProgressing,
+		RollingBack: true,
+	}
+	r.progressRollout(ctx, log, sr, workloads, checksum, false)
+}
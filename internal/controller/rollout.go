@@ -0,0 +1,220 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/rollout"
+)
+
+const (
+	// defaultStepTimeout is how long a wave may take to roll out
+	defaultStepTimeout = 10 * time.Minute
+	// rolloutPollInterval is how often the workloads of a wave are checked
+	rolloutPollInterval = 15 * time.Second
+)
+
+// eventRolloutCompleted is recorded when every wave of a Progressive rollout rolled out
+const eventRolloutCompleted = "RolloutCompleted"
+
+// rolloutWaves splits workloads into waves of size workloads
+func rolloutWaves(workloads []secretsv1alpha1.WorkloadReference, size int32) [][]secretsv1alpha1.WorkloadReference {
+	if size < 1 {
+		size = 1
+	}
+	var waves [][]secretsv1alpha1.WorkloadReference
+	for start := 0; start < len(workloads); start += int(size) {
+		end := min(start+int(size), len(workloads))
+		waves = append(waves, workloads[start:end])
+	}
+	return waves
+}
+
+// progressiveRollout reports whether the workloads of sr are restarted in
+// waves. Rollbacks need the health of the workloads, so with a rollback
+// policy every workload is restarted in a single watched wave by default.
+func progressiveRollout(sr *secretsv1alpha1.SecretRotation) bool {
+	strategy := sr.Spec.RolloutStrategy
+	return sr.Spec.Rollback != nil || (strategy != nil && strategy.Type == secretsv1alpha1.RolloutStrategyProgressive)
+}
+
+// rolloutInProgress reports whether sr is waiting for a wave to roll out
+func rolloutInProgress(sr *secretsv1alpha1.SecretRotation) bool {
+	return sr.Status.Rollout != nil && sr.Status.Rollout.Phase == secretsv1alpha1.RolloutPhaseProgressing
+}
+
+// rolloutHalted reports whether the last Progressive rollout of sr halted
+func rolloutHalted(sr *secretsv1alpha1.SecretRotation) bool {
+	return sr.Status.Rollout != nil && sr.Status.Rollout.Phase == secretsv1alpha1.RolloutPhaseHalted
+}
+
+// progressRollout advances the Progressive rollout of checksum: it waits
+// until the workloads of the current wave rolled out, then restarts the next
+// wave. A wave that fails or times out halts the rollout until the secret
+// changes again.
+func (r *SecretRotationReconciler) progressRollout(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation, workloads []secretsv1alpha1.WorkloadReference, checksum string, secretChanged bool) {
+	strategy := sr.Spec.RolloutStrategy
+	if strategy == nil {
+		strategy = &secretsv1alpha1.RolloutStrategy{}
+	}
+	waveSize := int32(len(workloads))
+	if strategy.Type == secretsv1alpha1.RolloutStrategyProgressive {
+		waveSize = strategy.WaveSize
+	}
+	waves := rolloutWaves(workloads, waveSize)
+	status := sr.Status.Rollout
+	if secretChanged || status == nil || status.Checksum != checksum {
+		status = &secretsv1alpha1.RolloutStatus{Checksum: checksum, Phase: secretsv1alpha1.RolloutPhaseProgressing}
+		sr.Status.Rollout = status
+		log.Info("Secret changed, starting a progressive rollout", "checksum", checksum, "waves", len(waves))
+	}
+	status.Waves = int32(len(waves))
+	if status.Phase != secretsv1alpha1.RolloutPhaseProgressing {
+		sr.Status.WorkloadRestartPending = false
+		return
+	}
+	sr.Status.WorkloadRestartPending = true
+
+	// Wait for the wave that was started last
+	if status.Wave > 0 && int(status.Wave) <= len(waves) {
+		state, message := r.waveStatus(ctx, sr, waves[status.Wave-1])
+		timeout := intervalOrDefault(strategy.StepTimeout, defaultStepTimeout)
+		switch {
+		case state == rollout.Failed:
+			r.haltRollout(ctx, log, sr, workloads, fmt.Sprintf("Wave %d of %d failed: %s", status.Wave, status.Waves, message))
+			return
+		case state == rollout.Progressing && status.WaveStartTime != nil && time.Since(status.WaveStartTime.Time) > timeout:
+			r.haltRollout(ctx, log, sr, workloads, fmt.Sprintf("Wave %d of %d did not roll out within %s: %s", status.Wave, status.Waves, timeout, message))
+			return
+		case state == rollout.Progressing:
+			status.Message = message
+			setRolloutCondition(sr, metav1.ConditionFalse, secretsv1alpha1.ReasonRolloutProgressing,
+				fmt.Sprintf("Waiting for wave %d of %d: %s", status.Wave, status.Waves, message))
+			return
+		}
+	}
+
+	if int(status.Wave) >= len(waves) {
+		status.Phase = secretsv1alpha1.RolloutPhaseCompleted
+		status.Workloads = nil
+		status.Generations = nil
+		status.Message = fmt.Sprintf("Restarted %d workloads in %d waves", len(workloads), len(waves))
+		sr.Status.WorkloadRestartPending = false
+		log.Info("Progressive rollout completed", "checksum", checksum, "waves", len(waves))
+		r.Recorder.Event(sr, corev1.EventTypeNormal, eventRolloutCompleted, status.Message)
+		setRolloutCondition(sr, metav1.ConditionTrue, secretsv1alpha1.ReasonWorkloadsRolled, status.Message)
+		return
+	}
+
+	// Start the next wave
+	wave := waves[status.Wave]
+	updated, generations, failures := r.restartBatch(ctx, log, sr, wave, checksum)
+	if status.Wave == 0 {
+		sr.Status.UpdatedWorkloads = nil
+	}
+	sr.Status.UpdatedWorkloads = append(sr.Status.UpdatedWorkloads, updated...)
+	status.Wave++
+	status.WaveStartTime = &metav1.Time{Time: time.Now()}
+	status.Generations = generations
+	status.Workloads = make([]string, 0, len(wave))
+	for _, workload := range wave {
+		status.Workloads = append(status.Workloads, workloadStatusKey(sr, workload))
+	}
+	if len(failures) > 0 {
+		r.haltRollout(ctx, log, sr, workloads, fmt.Sprintf("Wave %d of %d failed: %s", status.Wave, status.Waves, strings.Join(failures, "; ")))
+		return
+	}
+	status.Message = fmt.Sprintf("Restarted %s", strings.Join(status.Workloads, ", "))
+	log.Info("Started rollout wave", "wave", status.Wave, "waves", status.Waves, "workloads", status.Workloads)
+	setRolloutCondition(sr, metav1.ConditionFalse, secretsv1alpha1.ReasonRolloutProgressing,
+		fmt.Sprintf("Started wave %d of %d: %s", status.Wave, status.Waves, status.Message))
+}
+
+// waveStatus returns the combined rollout state of the workloads of a wave.
+// Workloads read from the cache before their restart reached it are still
+// progressing.
+func (r *SecretRotationReconciler) waveStatus(ctx context.Context, sr *secretsv1alpha1.SecretRotation, wave []secretsv1alpha1.WorkloadReference) (rollout.State, string) {
+	state := rollout.Complete
+	var messages []string
+	for _, workload := range wave {
+		namespace := workload.Namespace
+		if namespace == "" {
+			namespace = sr.Namespace
+		}
+		key := workloadStatusKey(sr, workload)
+		obj, err := workloadObject(workload, namespace)
+		if err == nil {
+			err = r.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		}
+		if err != nil {
+			state = rollout.Failed
+			messages = append(messages, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		workloadState, message := rollout.Status(obj)
+		if generation := sr.Status.Rollout.Generations[key]; obj.GetGeneration() < generation {
+			workloadState, message = rollout.Progressing, fmt.Sprintf("waiting for generation %d to be observed", generation)
+		}
+		switch {
+		case workloadState == rollout.Failed:
+			state = rollout.Failed
+		case workloadState == rollout.Progressing && state == rollout.Complete:
+			state = rollout.Progressing
+		}
+		if workloadState != rollout.Complete {
+			messages = append(messages, fmt.Sprintf("%s: %s", key, message))
+		}
+	}
+	return state, strings.Join(messages, "; ")
+}
+
+// haltRollout stops the Progressive rollout of sr; the remaining waves keep
+// the previous secret checksum. With a rollback policy the previous secret
+// data is restored, unless the failed rollout was a rollback itself.
+func (r *SecretRotationReconciler) haltRollout(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation, workloads []secretsv1alpha1.WorkloadReference, message string) {
+	sr.Status.Rollout.Phase = secretsv1alpha1.RolloutPhaseHalted
+	sr.Status.Rollout.Message = message
+	sr.Status.WorkloadRestartPending = false
+	log.Info("Progressive rollout halted", "reason", message)
+	r.Recorder.Event(sr, corev1.EventTypeWarning, secretsv1alpha1.ReasonRolloutHalted, message)
+	setRolloutCondition(sr, metav1.ConditionFalse, secretsv1alpha1.ReasonRolloutHalted, message)
+	if sr.Spec.Rollback != nil && !sr.Status.Rollout.RollingBack {
+		r.rollback(ctx, log, sr, workloads, message)
+	}
+}
+
+// setRolloutCondition sets the WorkloadsRolled condition of sr
+func setRolloutCondition(sr *secretsv1alpha1.SecretRotation, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&sr.Status.Conditions, metav1.Condition{
+		Type:               secretsv1alpha1.ConditionWorkloadsRolled,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: sr.Generation,
+	})
+}
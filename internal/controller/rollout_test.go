@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/rollout"
+)
+
+var _ = Describe("waveStatus", func() {
+	wave := []secretsv1alpha1.WorkloadReference{{Kind: "Deployment", Name: "api"}}
+
+	// statusOf returns the state of wave with the Deployment api at
+	// generation, rolled out, after a restart produced restartGeneration
+	statusOf := func(generation, restartGeneration int64) (rollout.State, string) {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", Generation: generation},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: generation,
+				Replicas:           1,
+				UpdatedReplicas:    1,
+				AvailableReplicas:  1,
+			},
+		}
+		r := &SecretRotationReconciler{
+			Client: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(deployment).Build(),
+		}
+		sr := &secretsv1alpha1.SecretRotation{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+		sr.Status.Rollout = &secretsv1alpha1.RolloutStatus{
+			Generations: map[string]int64{"Deployment/api": restartGeneration},
+		}
+		return r.waveStatus(context.Background(), sr, wave)
+	}
+
+	It("is complete once the generation of the restart rolled out", func() {
+		state, _ := statusOf(3, 3)
+		Expect(state).To(Equal(rollout.Complete))
+	})
+
+	It("waits for a workload read before its restart reached the cache", func() {
+		state, message := statusOf(2, 3)
+		Expect(state).To(Equal(rollout.Progressing))
+		Expect(message).To(Equal("Deployment/api: waiting for generation 3 to be observed"))
+	})
+})
@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	vault "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/vaultclient"
+)
+
+// defaultRotationHistoryLimit is the number of rotations kept in status
+const defaultRotationHistoryLimit = 10
+
+// rotationDue reports whether the keys of the secret read from Vault have to be regenerated
+func rotationDue(sr *secretsv1alpha1.SecretRotation, metadata *vault.KVVersionMetadata, now time.Time) (bool, error) {
+	if metadata == nil {
+		return false, nil
+	}
+	next, err := nextRotationAfter(sr, metadata.CreatedTime)
+	if err != nil {
+		return false, err
+	}
+	return !now.Before(next), nil
+}
+
+// nextRotation returns when the synced secret of sr is next rotated, if it is rotated at all
+func nextRotation(sr *secretsv1alpha1.SecretRotation) *time.Time {
+	synced := sr.Status.SyncedVersion
+	if sr.Spec.Rotation == nil || synced == nil || synced.CreatedTime == nil {
+		return nil
+	}
+	next, err := nextRotationAfter(sr, synced.CreatedTime.Time)
+	if err != nil {
+		return nil
+	}
+	return &next
+}
+
+// nextRotationAfter returns when a Vault version written at written is due for rotation
+func nextRotationAfter(sr *secretsv1alpha1.SecretRotation, written time.Time) (time.Time, error) {
+	policy := sr.Spec.Rotation
+	if policy.Schedule != "" {
+		schedule, err := parseSchedule(sr, policy.Schedule)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return schedule.Next(written), nil
+	}
+	if policy.Interval == nil {
+		return time.Time{}, fmt.Errorf("rotation needs an interval or a schedule")
+	}
+	return written.Add(policy.Interval.Duration), nil
+}
+
+// rotateVaultSecret writes a new version of current to Vault with the keys
+// of the rotation policy regenerated. The write is checked against the
+// version of current, so a concurrent change in Vault is never overwritten.
+func (r *SecretRotationReconciler) rotateVaultSecret(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation, vaultClient *vault.Client, current *vault.KVSecret) (*vault.KVSecret, error) {
+	policy := sr.Spec.Rotation
+	generator := vaultclient.PasswordGenerator{
+		Charset: policy.Generator.Charset,
+		Policy:  policy.Generator.PolicyName,
+	}
+	if policy.Generator.Length != nil {
+		generator.Length = int(*policy.Generator.Length)
+	}
+
+	data := maps.Clone(current.Data)
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	for _, key := range policy.Keys {
+		value, err := generator.Generate(ctx, vaultClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate a value for %s: %w", key, err)
+		}
+		data[key] = value
+	}
+
+	rotated, err := vaultclient.WriteKVv2(ctx, vaultClient, sr.Spec.Mount, sr.Spec.Path, data, current.VersionMetadata.Version)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Rotated secret in Vault", "version", rotated.VersionMetadata.Version, "keys", policy.Keys)
+	r.Recorder.Eventf(sr, corev1.EventTypeNormal, eventRotated,
+		"Wrote version %d of %s/%s to Vault with new values for %s", rotated.VersionMetadata.Version,
+		sr.Spec.Mount, sr.Spec.Path, strings.Join(policy.Keys, ", "))
+
+	// Record the rotation right away; Vault already holds the new values
+	limit := defaultRotationHistoryLimit
+	if policy.HistoryLimit != nil {
+		limit = int(*policy.HistoryLimit)
+	}
+	record := secretsv1alpha1.RotationRecord{
+		Time:    metav1.NewTime(rotated.VersionMetadata.CreatedTime),
+		Version: rotated.VersionMetadata.Version,
+		Keys:    policy.Keys,
+	}
+	if record.Time.IsZero() {
+		record.Time = metav1.Now()
+	}
+	history := append([]secretsv1alpha1.RotationRecord{record}, sr.Status.RotationHistory...)
+	sr.Status.RotationHistory = history[:min(len(history), limit)]
+	return rotated, r.setCondition(ctx, sr, secretsv1alpha1.ConditionRotated, metav1.ConditionTrue,
+		secretsv1alpha1.ReasonRotationSucceeded, fmt.Sprintf("Wrote version %d to Vault", record.Version))
+}
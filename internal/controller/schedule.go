@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+const (
+	// defaultRefreshInterval is how often a SecretRotation is synced with Vault
+	defaultRefreshInterval = 10 * time.Minute
+	// defaultRetryInterval is how soon a failed sync is retried
+	defaultRetryInterval = time.Minute
+)
+
+// cronParser parses five-field cron expressions and descriptors such as @weekly
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// parseSchedule parses a cron expression in the time zone of sr
+func parseSchedule(sr *secretsv1alpha1.SecretRotation, expr string) (cron.Schedule, error) {
+	spec := expr
+	if sr.Spec.TimeZone != "" {
+		spec = fmt.Sprintf("CRON_TZ=%s %s", sr.Spec.TimeZone, expr)
+	}
+	schedule, err := cronParser.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", expr, err)
+	}
+	return schedule, nil
+}
+
+// refreshInterval returns how often sr is synced with Vault
+func refreshInterval(sr *secretsv1alpha1.SecretRotation) time.Duration {
+	return intervalOrDefault(sr.Spec.RefreshInterval, defaultRefreshInterval)
+}
+
+// retryInterval returns how soon a failed sync of sr is retried
+func retryInterval(sr *secretsv1alpha1.SecretRotation) time.Duration {
+	return intervalOrDefault(sr.Spec.RetryInterval, defaultRetryInterval)
+}
+
+// intervalOrDefault returns interval if it is set and positive, otherwise def
+func intervalOrDefault(interval *metav1.Duration, def time.Duration) time.Duration {
+	if interval != nil && interval.Duration > 0 {
+		return interval.Duration
+	}
+	return def
+}
+
+// maintenanceWindowOpen reports whether workloads of sr may be restarted at
+// now and, if not, when the next maintenance window opens
+func maintenanceWindowOpen(sr *secretsv1alpha1.SecretRotation, now time.Time) (bool, time.Time, error) {
+	if len(sr.Spec.MaintenanceWindows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	var nextOpen time.Time
+	for _, window := range sr.Spec.MaintenanceWindows {
+		schedule, err := parseSchedule(sr, window.Schedule)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		// The window is open if it started within the last Duration
+		start := schedule.Next(now.Add(-window.Duration.Duration))
+		if !start.After(now) {
+			return true, time.Time{}, nil
+		}
+		if nextOpen.IsZero() || start.Before(nextOpen) {
+			nextOpen = start
+		}
+	}
+	return false, nextOpen, nil
+}
+
+// nextSync returns when sr has to be reconciled again: after the refresh
+// interval, or earlier when a lease, certificate, rotation, deferred workload
+// restart or rollout wave check is due
+func nextSync(sr *secretsv1alpha1.SecretRotation, now time.Time) time.Time {
+	var due []time.Time
+	if sr.Status.Lease != nil {
+		due = append(due, leaseActionTime(sr))
+	}
+	if sr.Status.Certificate != nil {
+		due = append(due, sr.Status.Certificate.RenewalTime.Time)
+	}
+	if next := nextRotation(sr); next != nil {
+		due = append(due, *next)
+	}
+	if sr.Status.WorkloadRestartPending {
+		if _, nextOpen, err := maintenanceWindowOpen(sr, now); err == nil && !nextOpen.IsZero() {
+			due = append(due, nextOpen)
+		}
+	}
+	if rolloutInProgress(sr) {
+		due = append(due, now.Add(rolloutPollInterval))
+	}
+
+	next := now.Add(refreshInterval(sr))
+	for _, t := range due {
+		if t.Before(next) {
+			next = t
+		}
+	}
+	// Never spin on something that is already due
+	if earliest := now.Add(time.Second); next.Before(earliest) {
+		next = earliest
+	}
+	return next
+}
@@ -23,30 +23,49 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	vault "github.com/hashicorp/vault/api"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/secretdata"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/vaultclient"
 )
 
 // +kubebuilder:rbac:groups=secrets.github.com,resources=secretrotations,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=secrets.github.com,resources=secretrotations/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=secrets.github.com,resources=secretrotations/finalizers,verbs=update
+// +kubebuilder:rbac:groups=secrets.github.com,resources=vaultauths;vaultconnections,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -59,9 +78,31 @@ import (
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
 type SecretRotationReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Vault  *vault.Client
-	Scheme *runtime.Scheme
+	Log   logr.Logger
+	Vault *vault.Client
+	// VaultToken reports whether Vault holds a valid token (optional)
+	VaultToken *vaultclient.TokenManager
+	// VaultClients caches clients for SecretRotations that reference a VaultAuth
+	VaultClients *vaultclient.ClientCache
+	Scheme       *runtime.Scheme
+	// Recorder records events on SecretRotations and their target workloads
+	// (defaults to the manager's recorder in SetupWithManager)
+	Recorder record.EventRecorder
+	// VaultEvents delivers Vault KV events that trigger an immediate sync of
+	// the SecretRotations reading the written path (optional)
+	VaultEvents <-chan event.TypedGenericEvent[vaultclient.Event]
+	// WorkloadKinds are the workload kinds besides the built-in ones whose
+	// drift is repaired as soon as it happens (optional). The operator needs
+	// RBAC to get, list and watch them.
+	WorkloadKinds []schema.GroupVersionKind
+	// CrossNamespaceWorkloads lets the namespace selector of a workload
+	// selector reach beyond the namespace of the SecretRotation. Anyone who
+	// can create a SecretRotation can then restart workloads in those
+	// namespaces, so it is off by default.
+	CrossNamespaceWorkloads bool
+
+	noOpEventsMu sync.Mutex
+	noOpEvents   map[types.NamespacedName]time.Time
 }
 
 func (r *SecretRotationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -72,235 +113,672 @@ func (r *SecretRotationReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	if err := r.Get(ctx, req.NamespacedName, &sr); err != nil {
 		if kerrors.IsNotFound(err) {
 			// Resource deleted
+			r.forgetNoOpSync(req.NamespacedName)
+			forgetMetrics(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
 
-	// Fetch secret data from Vault
-	secret, err := r.Vault.Logical().Read(sr.Spec.VaultPath)
+	// Apply the deletion policy before the SecretRotation goes away
+	if !sr.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, log, &sr)
+	}
+	if controllerutil.AddFinalizer(&sr, secretsv1alpha1.SecretRotationFinalizer) {
+		if err := r.Update(ctx, &sr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Report login failures separately from failed reads
+	vaultClient, err := r.vaultClientFor(ctx, &sr)
 	if err != nil {
-		log.Error(err, "failed to read from Vault", "path", sr.Spec.VaultPath)
-		return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+		log.Error(err, "not authenticated to Vault")
+		return r.retryLater(ctx, &sr, secretsv1alpha1.ConditionVaultReadable, secretsv1alpha1.ReasonVaultAuthFailed, err)
 	}
-	if secret == nil || secret.Data == nil {
-		log.Info("Vault secret not found or empty", "path", sr.Spec.VaultPath)
-		return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+
+	// Never take over a Secret managed by someone else. This and the templates
+	// are checked before dynamic engines issue credentials that would go unused.
+	k8sSecret := &corev1.Secret{}
+	secretErr := r.Get(ctx, client.ObjectKey{Namespace: req.Namespace, Name: sr.Spec.TargetSecret}, k8sSecret)
+	if secretErr != nil && !kerrors.IsNotFound(secretErr) {
+		return ctrl.Result{}, secretErr
+	}
+	if secretErr == nil {
+		if reason, claimErr := r.claimSecret(k8sSecret, &sr); claimErr != nil {
+			log.Error(claimErr, "refusing to manage Kubernetes Secret", "secret", sr.Spec.TargetSecret)
+			return r.retryLater(ctx, &sr, secretsv1alpha1.ConditionSecretSynced, reason, claimErr)
+		}
+	}
+	if sr.Spec.Template != nil {
+		if err := secretdata.Parse(sr.Spec.Template.Data); err != nil {
+			log.Error(err, "failed to parse secret template")
+			return r.retryLater(ctx, &sr, secretsv1alpha1.ConditionTemplateRendered, secretsv1alpha1.ReasonTemplateFailed, err)
+		}
 	}
 
-	// Vault KV v2 stores actual data under "data" key
-	data, ok := secret.Data["data"].(map[string]interface{})
-	if !ok {
-		data = secret.Data // fallback if not KV v2
+	// Dynamic credentials and certificates are only reissued when they are due
+	var keep bool
+	switch sr.Spec.Engine {
+	case secretsv1alpha1.SecretEngineDatabase:
+		keep, err = r.reconcileLease(ctx, log, &sr, vaultClient)
+	case secretsv1alpha1.SecretEnginePKI:
+		keep, err = r.reconcileCertificate(ctx, &sr)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// Render the target secret again from the current credentials when the
+	// spec changed since it was written or it was deleted or edited by hand
+	var reads []sourceRead
+	if keep && (!secretSyncedForSpec(&sr) || secretErr != nil || !targetSecretIntact(&sr, k8sSecret)) {
+		keep = false
+		if reads, err = r.storedCredentials(ctx, &sr); err != nil {
+			log.Error(err, "failed to read the stored credentials, requesting new ones")
+		}
+	}
+	if keep {
+		now := metav1.Now()
+		sr.Status.LastSyncTime = &now
+		// Restarts deferred to a maintenance window may still be due
+		workloads := r.resolveWorkloads(ctx, log, &sr)
+		unrestarted := r.repairWorkloadDrift(ctx, log, &sr, workloads)
+		r.restartWorkloads(ctx, log, &sr, workloads, sr.Status.SecretChecksum, unrestarted)
+		return r.updateStatus(ctx, log, &sr)
+	}
+
+	// Fetch secret data from Vault, unless the stored credentials are rendered again
+	sources := secretSources(&sr.Spec.SecretDataSpec)
+	source := describeSources(sources)
+	stored := reads != nil
+	if !stored {
+		var reason string
+		reads, reason, err = readSources(ctx, vaultClient, sources)
+		if err != nil {
+			log.Error(err, "failed to read from Vault", "source", source)
+			return r.retryLater(ctx, &sr, secretsv1alpha1.ConditionVaultReadable, reason, err)
+		}
+	}
+	kvSecret := reads[0].secret
+
+	// Revoke credentials issued by this sync unless they end up in the target secret
+	previousLease := sr.Status.Lease
+	var issuedLease string
+	if sr.Spec.Engine == secretsv1alpha1.SecretEngineDatabase && !stored {
+		issuedLease = kvSecret.Raw.LeaseID
+	}
+	defer func() {
+		if issuedLease != "" {
+			r.revokeLease(ctx, log, vaultClient, issuedLease)
+		}
+	}()
+
+	// Regenerate keys in Vault when the rotation policy says so
+	if sr.Spec.Rotation != nil {
+		due, err := rotationDue(&sr, kvSecret.VersionMetadata, time.Now())
+		if due && err == nil {
+			kvSecret, err = r.rotateVaultSecret(ctx, log, &sr, vaultClient, kvSecret)
+		}
+		if err != nil {
+			log.Error(err, "failed to rotate secret in Vault", "source", source)
+			return r.retryLater(ctx, &sr, secretsv1alpha1.ConditionRotated, secretsv1alpha1.ReasonRotationFailed, err)
+		}
+		reads[0].secret = kvSecret
+	}
+
+	// Decode binary values, select and rename the keys of each source and merge them
+	data, owners, reason, err := mergeSources(reads, sr.Spec.ConflictPolicy)
+	if err != nil {
+		log.Error(err, "failed to merge Vault data", "source", source)
+		return r.retryLater(ctx, &sr, secretsv1alpha1.ConditionSecretSynced, reason, err)
+	}
+
+	// Render config files and connection strings from the Vault data
+	if sr.Spec.Template != nil {
+		data, err = renderTemplate(&sr.Spec.SecretDataSpec, reads, data)
+		if err != nil {
+			log.Error(err, "failed to render secret template")
+			return r.retryLater(ctx, &sr, secretsv1alpha1.ConditionTemplateRendered, secretsv1alpha1.ReasonTemplateFailed, err)
+		}
+		meta.SetStatusCondition(&sr.Status.Conditions, metav1.Condition{
+			Type:               secretsv1alpha1.ConditionTemplateRendered,
+			Status:             metav1.ConditionTrue,
+			Reason:             secretsv1alpha1.ReasonTemplateRendered,
+			Message:            fmt.Sprintf("Rendered %d templated keys", len(sr.Spec.Template.Data)),
+			ObservedGeneration: sr.Generation,
+		})
+	} else {
+		meta.RemoveStatusCondition(&sr.Status.Conditions, secretsv1alpha1.ConditionTemplateRendered)
 	}
 
 	// Convert vault data to k8s secret data (map[string][]byte)
-	secretData := make(map[string][]byte)
-	for k, v := range data {
-		strVal := fmt.Sprintf("%v", v)
-		secretData[k] = []byte(strVal)
+	secretData, err := secretdata.EncodeAll(data)
+	if err != nil {
+		log.Error(err, "failed to encode Vault data", "source", source)
+		return r.retryLater(ctx, &sr, secretsv1alpha1.ConditionSecretSynced, secretsv1alpha1.ReasonValueEncodingFailed, err)
 	}
 
+	// Keep the data restored by a rollback while Vault still holds the rejected data
+	secretData, rejected := r.keepRolledBack(ctx, log, &sr, secretData)
+
 	// Calculate checksum of secret data
-	newChecksum := r.calculateSecretChecksum(secretData)
+	newChecksum := calculateSecretChecksum(secretData)
 	secretChanged := sr.Status.SecretChecksum != newChecksum
 
-	// Prepare Kubernetes Secret object
-	k8sSecret := &corev1.Secret{}
-	err = r.Get(ctx, client.ObjectKey{Namespace: req.Namespace, Name: sr.Spec.TargetSecret}, k8sSecret)
-	if err != nil && !kerrors.IsNotFound(err) {
-		return ctrl.Result{}, err
+	// The type of a Secret is immutable, so replace a Secret of the wrong type
+	if secretErr == nil && !targetSecretTypeMatches(&sr, k8sSecret) {
+		if err := r.Delete(ctx, k8sSecret); err != nil && !kerrors.IsNotFound(err) {
+			log.Error(err, "failed to replace Kubernetes Secret", "type", k8sSecret.Type)
+			return r.retryLater(ctx, &sr, secretsv1alpha1.ConditionSecretSynced, secretsv1alpha1.ReasonSecretSyncFailed, err)
+		}
+		log.Info("Replacing Kubernetes Secret of a different type", "secret", sr.Spec.TargetSecret, "type", k8sSecret.Type)
+		secretErr = kerrors.NewNotFound(corev1.Resource("secrets"), sr.Spec.TargetSecret)
 	}
 
-	if kerrors.IsNotFound(err) {
-		// Create Secret if not found
-		k8sSecret = &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      sr.Spec.TargetSecret,
-				Namespace: req.Namespace,
-			},
-			Data: secretData,
-		}
-		if err := r.Create(ctx, k8sSecret); err != nil {
-			log.Error(err, "failed to create Kubernetes Secret")
+	created := kerrors.IsNotFound(secretErr)
+	needUpdate := created || !secretDataEqual(k8sSecret.Data, secretData)
+	adopted := false
+	if !created {
+		if adopted, err = r.setSecretOwner(k8sSecret, &sr); err != nil {
 			return ctrl.Result{}, err
 		}
+		if adopted {
+			log.Info("Adopting Kubernetes Secret", "secret", sr.Spec.TargetSecret)
+			r.Recorder.Eventf(&sr, corev1.EventTypeNormal, eventSecretAdopted, "Managing existing secret %s", sr.Spec.TargetSecret)
+		}
+	}
+
+	// Create or update the Secret with server-side apply
+	if needUpdate || adopted {
+		if err := r.writeTargetSecret(ctx, &sr, secretData); err != nil {
+			log.Error(err, "failed to apply Kubernetes Secret")
+			return r.retryLater(ctx, &sr, secretsv1alpha1.ConditionSecretSynced, secretsv1alpha1.ReasonSecretSyncFailed, err)
+		}
+	}
+
+	// The credentials are in use now, and the ones they replace are not
+	if issuedLease != "" {
+		if previousLease != nil && previousLease.LeaseID != issuedLease {
+			r.revokeLease(ctx, log, vaultClient, previousLease.LeaseID)
+		}
+		issuedLease = ""
+	}
+
+	switch {
+	case created && secretChanged:
 		log.Info("Created Kubernetes Secret", "secret", sr.Spec.TargetSecret)
-		secretChanged = true
-	} else {
-		// Update existing Secret if data differs
-		needUpdate := false
-		if len(k8sSecret.Data) != len(secretData) {
-			needUpdate = true
+		secretUpdates.WithLabelValues(sr.Namespace, sr.Name, "create").Inc()
+		r.Recorder.Eventf(&sr, corev1.EventTypeNormal, eventSecretCreated,
+			"Created secret %s from Vault at %s", sr.Spec.TargetSecret, source)
+	case created:
+		// The data in Vault did not change, so the Secret was deleted by hand
+		log.Info("Recreated deleted Kubernetes Secret", "secret", sr.Spec.TargetSecret)
+		driftRepairs.WithLabelValues("Secret").Inc()
+		r.Recorder.Eventf(&sr, corev1.EventTypeNormal, eventDriftRepaired,
+			"Recreated secret %s from Vault at %s", sr.Spec.TargetSecret, source)
+	case needUpdate && !secretChanged:
+		// The data in Vault did not change, so the Secret was edited by hand
+		log.Info("Repaired drifted Kubernetes Secret", "secret", sr.Spec.TargetSecret)
+		driftRepairs.WithLabelValues("Secret").Inc()
+		r.Recorder.Eventf(&sr, corev1.EventTypeNormal, eventDriftRepaired,
+			"Restored secret %s from Vault at %s", sr.Spec.TargetSecret, source)
+	case needUpdate:
+		log.Info("Updated Kubernetes Secret", "secret", sr.Spec.TargetSecret)
+		secretUpdates.WithLabelValues(sr.Namespace, sr.Name, "update").Inc()
+		r.Recorder.Eventf(&sr, corev1.EventTypeNormal, eventSecretUpdated,
+			"Updated secret %s from Vault at %s", sr.Spec.TargetSecret, source)
+	default:
+		log.Info("Kubernetes Secret already up-to-date", "secret", sr.Spec.TargetSecret)
+		if r.noOpSyncDue(&sr) {
+			r.Recorder.Eventf(&sr, corev1.EventTypeNormal, eventSecretUpToDate,
+				"Secret %s is already up to date with Vault at %s", sr.Spec.TargetSecret, source)
+		}
+	}
+
+	// Record sync and change times and the checksum; a rollback while
+	// restarting workloads below replaces them
+	now := metav1.Now()
+	sr.Status.LastSyncTime = &now
+	if secretChanged {
+		sr.Status.LastChangeTime = &now
+		sr.Status.LastRotation = now
+	}
+	sr.Status.SecretChecksum = newChecksum
+
+	// Remember the payload to roll back to if a later one fails to roll out
+	if sr.Spec.Rollback != nil && !rejected {
+		var version int
+		if len(sr.Spec.Sources) == 0 && kvSecret.VersionMetadata != nil {
+			version = kvSecret.VersionMetadata.Version
+		}
+		if err := r.recordPayload(ctx, &sr, newChecksum, secretData, version); err != nil {
+			log.Error(err, "failed to record the secret data for rollbacks")
+		}
+	}
+
+	// Update target workloads if secret changed or new ones were added, or
+	// put back annotations removed since
+	workloads := r.resolveWorkloads(ctx, log, &sr)
+	unrestarted := false
+	if !secretChanged {
+		unrestarted = r.repairWorkloadDrift(ctx, log, &sr, workloads)
+	}
+	r.restartWorkloads(ctx, log, &sr, workloads, newChecksum, secretChanged || unrestarted)
+
+	// Update status with the synced version, unless it was rolled back from
+	rejected = sr.Status.Rollback != nil && sr.Status.Rollback.RejectedChecksum != ""
+	if !rejected {
+		sr.Status.SyncedVersion = nil
+		sr.Status.Sources = nil
+		if len(sr.Spec.Sources) == 0 {
+			sr.Status.SyncedVersion = syncedVersion(kvSecret.VersionMetadata)
 		} else {
-			for k, v := range secretData {
-				if string(k8sSecret.Data[k]) != string(v) {
-					needUpdate = true
-					break
-				}
+			sr.Status.Sources = sourceStatuses(reads, owners)
+		}
+	}
+	if !stored {
+		sr.Status.Lease = nil
+		sr.Status.Certificate = nil
+		switch sr.Spec.Engine {
+		case secretsv1alpha1.SecretEngineDatabase:
+			sr.Status.Lease = newLease(kvSecret.Raw)
+		case secretsv1alpha1.SecretEnginePKI:
+			sr.Status.Certificate = issuedCertificate(secretData, sr.Spec.PKI)
+		}
+		// Keep the Vault data of new credentials to render the target secret from later
+		if sr.Status.Lease != nil || sr.Status.Certificate != nil {
+			if err := r.storeCredentials(ctx, &sr, kvSecret.Data); err != nil {
+				log.Error(err, "failed to store the Vault credentials")
 			}
 		}
-		if needUpdate {
-			k8sSecret.Data = secretData
-			if err := r.Update(ctx, k8sSecret); err != nil {
-				log.Error(err, "failed to update Kubernetes Secret")
-				return ctrl.Result{}, err
+	}
+	meta.SetStatusCondition(&sr.Status.Conditions, metav1.Condition{
+		Type:               secretsv1alpha1.ConditionVaultReadable,
+		Status:             metav1.ConditionTrue,
+		Reason:             secretsv1alpha1.ReasonSecretRead,
+		Message:            fmt.Sprintf("Read secret from Vault at %s", source),
+		ObservedGeneration: sr.Generation,
+	})
+	synced := metav1.Condition{
+		Type:               secretsv1alpha1.ConditionSecretSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             secretsv1alpha1.ReasonSecretSynced,
+		Message:            fmt.Sprintf("Secret %s is up to date", sr.Spec.TargetSecret),
+		ObservedGeneration: sr.Generation,
+	}
+	if rejected {
+		synced.Status = metav1.ConditionFalse
+		synced.Reason = secretsv1alpha1.ReasonDataRejected
+		synced.Message = fmt.Sprintf("Secret %s keeps the rolled back data until the data in Vault at %s changes", sr.Spec.TargetSecret, source)
+	}
+	meta.SetStatusCondition(&sr.Status.Conditions, synced)
+	return r.updateStatus(ctx, log, &sr)
+}
+
+// updateStatus records when sr is next synced and rotated, saves its status
+// and requeues it for the next sync
+func (r *SecretRotationReconciler) updateStatus(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation) (ctrl.Result, error) {
+	now := time.Now()
+	next := nextSync(sr, now)
+	sr.Status.NextScheduledSync = &metav1.Time{Time: next}
+	sr.Status.NextRotation = nil
+	if rotation := nextRotation(sr); rotation != nil {
+		sr.Status.NextRotation = &metav1.Time{Time: *rotation}
+	}
+	if err := r.saveStatus(ctx, sr); err != nil {
+		log.Error(err, "failed to update SecretRotation status")
+		return ctrl.Result{}, err
+	}
+	recordTimeMetrics(sr)
+	return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+}
+
+// retryLater records a failure in the conditionType condition and retries
+// after the retry interval of sr
+func (r *SecretRotationReconciler) retryLater(ctx context.Context, sr *secretsv1alpha1.SecretRotation, conditionType, reason string, err error) (ctrl.Result, error) {
+	r.Recorder.Event(sr, corev1.EventTypeWarning, reason, err.Error())
+	retry := retryInterval(sr)
+	sr.Status.NextScheduledSync = &metav1.Time{Time: time.Now().Add(retry)}
+	saveErr := r.setCondition(ctx, sr, conditionType, metav1.ConditionFalse, reason, err.Error())
+	// Keep the sync age moving while every sync since the operator started failed
+	recordTimeMetrics(sr)
+	return ctrl.Result{RequeueAfter: retry}, saveErr
+}
+
+// setCondition records a condition in the SecretRotation status
+func (r *SecretRotationReconciler) setCondition(ctx context.Context, sr *secretsv1alpha1.SecretRotation, conditionType string, status metav1.ConditionStatus, reason, message string) error {
+	meta.SetStatusCondition(&sr.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: sr.Generation,
+	})
+	return r.saveStatus(ctx, sr)
+}
+
+// saveStatus summarizes the conditions of sr in its Ready condition and saves its status
+func (r *SecretRotationReconciler) saveStatus(ctx context.Context, sr *secretsv1alpha1.SecretRotation) error {
+	sr.Status.ObservedGeneration = sr.Generation
+	setReadyCondition(sr)
+	return r.Status().Update(ctx, sr)
+}
+
+// setReadyCondition sets the Ready condition of sr from its other conditions.
+// VaultReadable and SecretSynced have to be true; Rotated, TemplateRendered
+// and WorkloadsRolled only count once they were reported.
+func setReadyCondition(sr *secretsv1alpha1.SecretRotation) {
+	ready := metav1.Condition{
+		Type:    secretsv1alpha1.ConditionReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  secretsv1alpha1.ReasonReady,
+		Message: fmt.Sprintf("Secret %s is synced from Vault", sr.Spec.TargetSecret),
+	}
+	for _, conditionType := range []string{
+		secretsv1alpha1.ConditionVaultReadable,
+		secretsv1alpha1.ConditionRotated,
+		secretsv1alpha1.ConditionTemplateRendered,
+		secretsv1alpha1.ConditionSecretSynced,
+		secretsv1alpha1.ConditionWorkloadsRolled,
+	} {
+		condition := meta.FindStatusCondition(sr.Status.Conditions, conditionType)
+		if condition == nil {
+			if conditionType == secretsv1alpha1.ConditionVaultReadable || conditionType == secretsv1alpha1.ConditionSecretSynced {
+				ready.Status = metav1.ConditionUnknown
+				ready.Reason = secretsv1alpha1.ReasonReconciling
+				ready.Message = fmt.Sprintf("Waiting for the %s condition", conditionType)
+				break
 			}
-			log.Info("Updated Kubernetes Secret", "secret", sr.Spec.TargetSecret)
-			secretChanged = true
-		} else {
-			log.Info("Kubernetes Secret already up-to-date", "secret", sr.Spec.TargetSecret)
+			continue
+		}
+		if condition.Status != metav1.ConditionTrue {
+			ready.Status = metav1.ConditionFalse
+			ready.Reason = condition.Reason
+			ready.Message = condition.Message
+			break
 		}
 	}
+	ready.ObservedGeneration = sr.Generation
+	meta.SetStatusCondition(&sr.Status.Conditions, ready)
+}
 
-	// Update target workloads if secret changed
-	var updatedWorkloads []string
-	if secretChanged && len(sr.Spec.TargetWorkloads) > 0 {
-		log.Info("Secret changed, updating target workloads", "checksum", newChecksum)
-		
-		annotationPrefix := sr.Spec.AnnotationPrefix
-		if annotationPrefix == "" {
-			annotationPrefix = "secrets.github.com/"
+// restartWorkloads annotates the workloads targeted by sr with checksum when
+// the secret changed or an earlier restart is still pending. Outside of the
+// maintenance windows of sr the restart is deferred instead.
+func (r *SecretRotationReconciler) restartWorkloads(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation, workloads []secretsv1alpha1.WorkloadReference, checksum string, secretChanged bool) {
+	if len(workloads) == 0 {
+		sr.Status.WorkloadRestartPending = false
+		sr.Status.Rollout = nil
+		meta.RemoveStatusCondition(&sr.Status.Conditions, secretsv1alpha1.ConditionWorkloadsRolled)
+		return
+	}
+	if !secretChanged && !sr.Status.WorkloadRestartPending {
+		return
+	}
+
+	open, nextOpen, err := maintenanceWindowOpen(sr, time.Now())
+	if err != nil {
+		log.Error(err, "invalid maintenance window, deferring workload restarts")
+	}
+	if !open {
+		message := fmt.Sprintf("Waiting for the maintenance window opening at %s", nextOpen.Format(time.RFC3339))
+		if err != nil {
+			message = err.Error()
+		} else {
+			log.Info("Deferring workload restarts to the next maintenance window", "opens", nextOpen)
 		}
-		
-		for _, workload := range sr.Spec.TargetWorkloads {
-			err := r.updateWorkloadAnnotation(ctx, log, workload, sr.Namespace, annotationPrefix, newChecksum)
-			if err != nil {
-				log.Error(err, "failed to update workload", "kind", workload.Kind, "name", workload.Name)
-				continue
-			}
-			workloadKey := fmt.Sprintf("%s/%s", workload.Kind, workload.Name)
-			if workload.Namespace != "" && workload.Namespace != sr.Namespace {
-				workloadKey = fmt.Sprintf("%s/%s/%s", workload.Namespace, workload.Kind, workload.Name)
-			}
-			updatedWorkloads = append(updatedWorkloads, workloadKey)
-			log.Info("Updated workload annotation", "kind", workload.Kind, "name", workload.Name, "checksum", newChecksum)
+		if secretChanged {
+			r.Recorder.Event(sr, corev1.EventTypeNormal, eventWorkloadRestartDeferred, message)
 		}
+		sr.Status.WorkloadRestartPending = true
+		meta.SetStatusCondition(&sr.Status.Conditions, metav1.Condition{
+			Type:               secretsv1alpha1.ConditionWorkloadsRolled,
+			Status:             metav1.ConditionFalse,
+			Reason:             secretsv1alpha1.ReasonWorkloadRestartPending,
+			Message:            message,
+			ObservedGeneration: sr.Generation,
+		})
+		return
 	}
 
-	// Update status with last rotation time and checksum
-	sr.Status.LastRotation = metav1.Now()
-	sr.Status.SecretChecksum = newChecksum
+	// Restart the workloads in health-gated waves instead when asked to, or
+	// watch their health to roll back
+	if progressiveRollout(sr) {
+		r.progressRollout(ctx, log, sr, workloads, checksum, secretChanged)
+		return
+	}
+	sr.Status.Rollout = nil
+
+	log.Info("Secret changed, updating target workloads", "checksum", checksum)
+	updatedWorkloads, _, failures := r.restartBatch(ctx, log, sr, workloads, checksum)
 	if len(updatedWorkloads) > 0 {
 		sr.Status.UpdatedWorkloads = updatedWorkloads
 	}
-	if err := r.Status().Update(ctx, &sr); err != nil {
-		log.Error(err, "failed to update SecretRotation status")
-		return ctrl.Result{}, err
+
+	// Failed restarts are retried on the next sync
+	sr.Status.WorkloadRestartPending = len(failures) > 0
+	condition := metav1.Condition{
+		Type:               secretsv1alpha1.ConditionWorkloadsRolled,
+		Status:             metav1.ConditionTrue,
+		Reason:             secretsv1alpha1.ReasonWorkloadsRolled,
+		Message:            fmt.Sprintf("Restarted %d workloads", len(updatedWorkloads)),
+		ObservedGeneration: sr.Generation,
+	}
+	if len(failures) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = secretsv1alpha1.ReasonWorkloadRolloutFailed
+		condition.Message = "Failed to restart " + strings.Join(failures, "; ")
+	}
+	meta.SetStatusCondition(&sr.Status.Conditions, condition)
+}
+
+// restartBatch annotates workloads with checksum and returns the workloads
+// that were restarted and the failures
+func (r *SecretRotationReconciler) restartBatch(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation, workloads []secretsv1alpha1.WorkloadReference, checksum string) ([]string, map[string]int64, []string) {
+	var updatedWorkloads, failures []string
+	generations := make(map[string]int64, len(workloads))
+
+	annotationPrefix := sr.Spec.AnnotationPrefix
+	if annotationPrefix == "" {
+		annotationPrefix = "secrets.github.com/"
+	}
+
+	for _, workload := range workloads {
+		workloadKey := workloadStatusKey(sr, workload)
+
+		// Events are recorded on the fetched workload, since only those
+		// matching its UID are shown for it
+		obj, err := r.updateWorkloadAnnotation(ctx, log, workload, sr.Namespace, annotationPrefix, checksum)
+		if err != nil {
+			log.Error(err, "failed to update workload", "kind", workload.Kind, "name", workload.Name)
+			failures = append(failures, fmt.Sprintf("%s: %v", workloadKey, err))
+			workloadRolloutFailures.WithLabelValues(workload.Kind).Inc()
+			r.Recorder.Eventf(sr, corev1.EventTypeWarning, eventWorkloadRestartFailed,
+				"Failed to restart %s: %v", workloadKey, err)
+			if obj != nil {
+				r.Recorder.Eventf(obj, corev1.EventTypeWarning, eventWorkloadRestartFailed,
+					"SecretRotation %s/%s failed to restart pods for changed secret %s: %v", sr.Namespace, sr.Name, sr.Spec.TargetSecret, err)
+			}
+			continue
+		}
+		updatedWorkloads = append(updatedWorkloads, workloadKey)
+		// The patch response carries the generation the restart produced
+		generations[workloadKey] = obj.GetGeneration()
+		workloadRollouts.WithLabelValues(workload.Kind).Inc()
+		log.Info("Updated workload annotation", "kind", workload.Kind, "name", workload.Name, "checksum", checksum)
+		r.Recorder.Eventf(sr, corev1.EventTypeNormal, eventWorkloadRestarted,
+			"Restarted %s for secret %s (checksum %s)", workloadKey, sr.Spec.TargetSecret, checksum)
+		r.Recorder.Eventf(obj, corev1.EventTypeNormal, eventWorkloadRestarted,
+			"Restarting pods: secret %s/%s changed (checksum %s, SecretRotation %s)", sr.Namespace, sr.Spec.TargetSecret, checksum, sr.Name)
 	}
+	return updatedWorkloads, generations, failures
+}
+
+// secretSyncedForSpec reports whether the target secret of sr was last
+// written for its current spec
+func secretSyncedForSpec(sr *secretsv1alpha1.SecretRotation) bool {
+	synced := meta.FindStatusCondition(sr.Status.Conditions, secretsv1alpha1.ConditionSecretSynced)
+	return synced != nil && synced.Status == metav1.ConditionTrue && synced.ObservedGeneration == sr.Generation
+}
+
+// targetSecretIntact reports whether secret still holds the data last
+// written to it for sr, with the right type
+func targetSecretIntact(sr *secretsv1alpha1.SecretRotation, secret *corev1.Secret) bool {
+	return targetSecretTypeMatches(sr, secret) && calculateSecretChecksum(secret.Data) == sr.Status.SecretChecksum
+}
 
-	return ctrl.Result{RequeueAfter: 10 * time.Minute}, nil // rotate every 10 minutes or adjust as needed
+// targetSecretTypeMatches reports whether secret has the type sr writes
+func targetSecretTypeMatches(sr *secretsv1alpha1.SecretRotation, secret *corev1.Secret) bool {
+	secretType := targetSecretType(sr.Spec.VaultSource)
+	return secret.Type == secretType || (secret.Type == "" && secretType == corev1.SecretTypeOpaque)
 }
 
 // calculateSecretChecksum calculates a SHA256 checksum of the secret data
-func (r *SecretRotationReconciler) calculateSecretChecksum(data map[string][]byte) string {
+func calculateSecretChecksum(data map[string][]byte) string {
 	hash := sha256.New()
-	
+
 	// Sort keys to ensure consistent hashing
 	keys := make([]string, 0, len(data))
 	for k := range data {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
-	
+
 	// Hash key-value pairs in sorted order
 	for _, k := range keys {
 		hash.Write([]byte(k))
 		hash.Write(data[k])
 	}
-	
+
 	return hex.EncodeToString(hash.Sum(nil))[:16] // Use first 16 characters for brevity
 }
 
-// updateWorkloadAnnotation updates the specified workload with a checksum annotation
-func (r *SecretRotationReconciler) updateWorkloadAnnotation(ctx context.Context, log logr.Logger, workload secretsv1alpha1.WorkloadReference, defaultNamespace, annotationPrefix, checksum string) error {
+// writeTargetSecret writes data to the target secret of sr with server-side apply
+func (r *SecretRotationReconciler) writeTargetSecret(ctx context.Context, sr *secretsv1alpha1.SecretRotation, data map[string][]byte) error {
+	desired := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sr.Spec.TargetSecret,
+			Namespace: sr.Namespace,
+		},
+		Type: targetSecretType(sr.Spec.VaultSource),
+		Data: data,
+	}
+	if _, err := r.setSecretOwner(desired, sr); err != nil {
+		return err
+	}
+	return applySecret(ctx, r.Client, desired)
+}
+
+// updateWorkloadAnnotation updates the specified workload with a checksum
+// annotation. It returns the workload as fetched, or nil when it could not be.
+func (r *SecretRotationReconciler) updateWorkloadAnnotation(ctx context.Context, log logr.Logger, workload secretsv1alpha1.WorkloadReference, defaultNamespace, annotationPrefix, checksum string) (client.Object, error) {
 	namespace := workload.Namespace
 	if namespace == "" {
 		namespace = defaultNamespace
 	}
-	
+
 	annotationKey := annotationPrefix + "secret-checksum"
-	
-	switch strings.ToLower(workload.Kind) {
-	case "deployment":
-		return r.updateDeploymentAnnotation(ctx, namespace, workload.Name, annotationKey, checksum)
-	case "statefulset":
-		return r.updateStatefulSetAnnotation(ctx, namespace, workload.Name, annotationKey, checksum)
-	case "daemonset":
-		return r.updateDaemonSetAnnotation(ctx, namespace, workload.Name, annotationKey, checksum)
-	case "replicaset":
-		return r.updateReplicaSetAnnotation(ctx, namespace, workload.Name, annotationKey, checksum)
-	default:
-		return fmt.Errorf("unsupported workload kind: %s", workload.Kind)
-	}
-}
 
-func (r *SecretRotationReconciler) updateDeploymentAnnotation(ctx context.Context, namespace, name, annotationKey, checksum string) error {
-	deployment := &appsv1.Deployment{}
-	key := types.NamespacedName{Namespace: namespace, Name: name}
-	
-	if err := r.Get(ctx, key, deployment); err != nil {
-		return err
+	obj, err := workloadObject(workload, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return nil, err
 	}
-	
-	if deployment.Spec.Template.Annotations == nil {
-		deployment.Spec.Template.Annotations = make(map[string]string)
+	// A wrong pod template path would otherwise add the annotation to a new field
+	path := podTemplatePath(workload)
+	if _, err := podTemplateAnnotations(obj, path); err != nil {
+		return obj, err
 	}
-	deployment.Spec.Template.Annotations[annotationKey] = checksum
-	
-	return r.Update(ctx, deployment)
+	return obj, patchPodTemplateAnnotation(ctx, r.Client, obj, path, annotationKey, checksum)
 }
 
-func (r *SecretRotationReconciler) updateStatefulSetAnnotation(ctx context.Context, namespace, name, annotationKey, checksum string) error {
-	statefulSet := &appsv1.StatefulSet{}
-	key := types.NamespacedName{Namespace: namespace, Name: name}
-	
-	if err := r.Get(ctx, key, statefulSet); err != nil {
+func (r *SecretRotationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("secretrotation-controller")
+	}
+
+	ctx := context.Background()
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &secretsv1alpha1.SecretRotation{}, vaultAuthRefField,
+		func(obj client.Object) []string {
+			if ref := obj.(*secretsv1alpha1.SecretRotation).Spec.VaultAuthRef; ref != "" {
+				return []string{ref}
+			}
+			return nil
+		}); err != nil {
 		return err
 	}
-	
-	if statefulSet.Spec.Template.Annotations == nil {
-		statefulSet.Spec.Template.Annotations = make(map[string]string)
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &secretsv1alpha1.SecretRotation{}, targetSecretField,
+		func(obj client.Object) []string {
+			return []string{obj.(*secretsv1alpha1.SecretRotation).Spec.TargetSecret}
+		}); err != nil {
+		return err
 	}
-	statefulSet.Spec.Template.Annotations[annotationKey] = checksum
-	
-	return r.Update(ctx, statefulSet)
-}
-
-func (r *SecretRotationReconciler) updateDaemonSetAnnotation(ctx context.Context, namespace, name, annotationKey, checksum string) error {
-	daemonSet := &appsv1.DaemonSet{}
-	key := types.NamespacedName{Namespace: namespace, Name: name}
-	
-	if err := r.Get(ctx, key, daemonSet); err != nil {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &secretsv1alpha1.SecretRotation{}, targetWorkloadField,
+		targetWorkloadKeys); err != nil {
 		return err
 	}
-	
-	if daemonSet.Spec.Template.Annotations == nil {
-		daemonSet.Spec.Template.Annotations = make(map[string]string)
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &secretsv1alpha1.SecretRotation{}, workloadSelectionField,
+		workloadSelectionKeys); err != nil {
+		return err
 	}
-	daemonSet.Spec.Template.Annotations[annotationKey] = checksum
-	
-	return r.Update(ctx, daemonSet)
-}
-
-func (r *SecretRotationReconciler) updateReplicaSetAnnotation(ctx context.Context, namespace, name, annotationKey, checksum string) error {
-	replicaSet := &appsv1.ReplicaSet{}
-	key := types.NamespacedName{Namespace: namespace, Name: name}
-	
-	if err := r.Get(ctx, key, replicaSet); err != nil {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &secretsv1alpha1.SecretRotation{}, vaultPathField,
+		vaultDataPathKeys); err != nil {
 		return err
 	}
-	
-	if replicaSet.Spec.Template.Annotations == nil {
-		replicaSet.Spec.Template.Annotations = make(map[string]string)
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &secretsv1alpha1.VaultAuth{}, vaultConnectionRefField,
+		func(obj client.Object) []string {
+			if ref := obj.(*secretsv1alpha1.VaultAuth).Spec.VaultConnectionRef; ref != "" {
+				return []string{ref}
+			}
+			return nil
+		}); err != nil {
+		return err
 	}
-	replicaSet.Spec.Template.Annotations[annotationKey] = checksum
-	
-	return r.Update(ctx, replicaSet)
-}
 
-func (r *SecretRotationReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&secretsv1alpha1.SecretRotation{}).
-		Complete(r)
+	// Label changes can add workloads to or remove them from a workload selector
+	workloadPredicates := builder.WithPredicates(
+		predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}))
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&secretsv1alpha1.SecretRotation{},
+			// Status updates must not cut the scheduled requeue short
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&secretsv1alpha1.VaultAuth{},
+			handler.EnqueueRequestsFromMapFunc(r.secretRotationsForVaultAuth)).
+		Watches(&secretsv1alpha1.VaultConnection{},
+			handler.EnqueueRequestsFromMapFunc(r.secretRotationsForVaultConnection)).
+		// Repair target secrets and workloads changed behind the operator's back
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.secretRotationsForSecret)).
+		Watches(&appsv1.Deployment{},
+			handler.EnqueueRequestsFromMapFunc(r.secretRotationsForWorkload),
+			workloadPredicates).
+		Watches(&appsv1.StatefulSet{},
+			handler.EnqueueRequestsFromMapFunc(r.secretRotationsForWorkload),
+			workloadPredicates).
+		Watches(&appsv1.DaemonSet{},
+			handler.EnqueueRequestsFromMapFunc(r.secretRotationsForWorkload),
+			workloadPredicates).
+		Watches(&appsv1.ReplicaSet{},
+			handler.EnqueueRequestsFromMapFunc(r.secretRotationsForWorkload),
+			workloadPredicates).
+		Watches(&batchv1.CronJob{},
+			handler.EnqueueRequestsFromMapFunc(r.secretRotationsForWorkload),
+			workloadPredicates)
+	// Other kinds are unknown to the scheme and only their metadata is needed
+	// to find the SecretRotations targeting them
+	for _, gvk := range r.WorkloadKinds {
+		workload := &metav1.PartialObjectMetadata{}
+		workload.SetGroupVersionKind(gvk)
+		b = b.Watches(workload,
+			handler.EnqueueRequestsFromMapFunc(r.secretRotationsForWorkload),
+			workloadPredicates)
+	}
+	// Sync right after a write in Vault; the periodic requeue stays as the fallback
+	if r.VaultEvents != nil {
+		b = b.WatchesRawSource(source.Channel(r.VaultEvents,
+			handler.TypedEnqueueRequestsFromMapFunc(r.secretRotationsForVaultEvent)))
+	}
+	return b.Complete(r)
 }
@@ -18,11 +18,19 @@ package controller
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 
+	vault "github.com/hashicorp/vault/api"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,45 +48,143 @@ var _ = Describe("SecretRotation Controller", func() {
 			Name:      resourceName,
 			Namespace: "default", // TODO(user):Modify as needed
 		}
+		targetSecretName := types.NamespacedName{Name: "test-secret", Namespace: "default"}
 		secretrotation := &secretsv1alpha1.SecretRotation{}
+		var controllerReconciler *SecretRotationReconciler
 
 		BeforeEach(func() {
+			By("starting a fake Vault serving the KV v2 secret")
+			mux := http.NewServeMux()
+			mux.HandleFunc("/v1/secret/data/test", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"data": {` +
+					`"data": {"username": "app", "password": "s3cr3t"}, ` +
+					`"metadata": {"version": 1, "created_time": "2025-01-01T00:00:00Z", "deletion_time": "", "destroyed": false}}}`))
+			})
+			server := httptest.NewServer(mux)
+			DeferCleanup(server.Close)
+
+			vaultConfig := vault.DefaultConfig()
+			vaultConfig.Address = server.URL
+			vaultClient, err := vault.NewClient(vaultConfig)
+			Expect(err).NotTo(HaveOccurred())
+			vaultClient.SetToken("test-token")
+
+			controllerReconciler = &SecretRotationReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(100),
+				Vault:    vaultClient,
+			}
+
 			By("creating the custom resource for the Kind SecretRotation")
-			err := k8sClient.Get(ctx, typeNamespacedName, secretrotation)
+			err = k8sClient.Get(ctx, typeNamespacedName, secretrotation)
 			if err != nil && errors.IsNotFound(err) {
 				resource := &secretsv1alpha1.SecretRotation{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: secretsv1alpha1.SecretRotationSpec{
+						SecretDataSpec: secretsv1alpha1.SecretDataSpec{
+							VaultSource: secretsv1alpha1.VaultSource{
+								Engine: secretsv1alpha1.SecretEngineKVv2,
+								Mount:  "secret",
+								Path:   "test",
+							},
+						},
+						TargetSecret: targetSecretName.Name,
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
 		})
 
 		AfterEach(func() {
-			// TODO(user): Cleanup logic after each test, like removing the resource instance.
 			resource := &secretsv1alpha1.SecretRotation{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
-			Expect(err).NotTo(HaveOccurred())
+			if err == nil {
+				By("Removing the finalizer so the resource can go away")
+				controllerutil.RemoveFinalizer(resource, secretsv1alpha1.SecretRotationFinalizer)
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
 
-			By("Cleanup the specific resource instance SecretRotation")
-			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
-		})
-		It("should successfully reconcile the resource", func() {
-			By("Reconciling the created resource")
-			controllerReconciler := &SecretRotationReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				By("Cleanup the specific resource instance SecretRotation")
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+			} else {
+				Expect(errors.IsNotFound(err)).To(BeTrue())
 			}
 
+			By("Cleanup the target secret")
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: targetSecretName.Name, Namespace: targetSecretName.Namespace}}
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+		})
+
+		reconcileResource := func() {
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+		}
+
+		// deleteWithPolicy syncs the secret, deletes the SecretRotation with
+		// policy and returns the target secret left behind, if any
+		deleteWithPolicy := func(policy secretsv1alpha1.DeletionPolicy) *corev1.Secret {
+			reconcileResource()
+
+			resource := &secretsv1alpha1.SecretRotation{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DeletionPolicy = policy
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			By("Applying the deletion policy in the finalizer")
+			reconcileResource()
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			secret := &corev1.Secret{}
+			err = k8sClient.Get(ctx, targetSecretName, secret)
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			Expect(err).NotTo(HaveOccurred())
+			return secret
+		}
+
+		It("should successfully reconcile the resource", func() {
+			By("Reconciling the created resource")
+			reconcileResource()
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, targetSecretName, secret)).To(Succeed())
+			Expect(secret.Data).To(Equal(map[string][]byte{"username": []byte("app"), "password": []byte("s3cr3t")}))
+			Expect(secret.Labels).To(HaveKeyWithValue(secretsv1alpha1.ManagedByLabel, resourceName))
+
+			resource := &secretsv1alpha1.SecretRotation{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(metav1.IsControlledBy(secret, resource)).To(BeTrue())
+			Expect(resource.Finalizers).To(ContainElement(secretsv1alpha1.SecretRotationFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, secretsv1alpha1.ConditionReady)).To(BeTrue())
+			Expect(resource.Status.SyncedVersion.Version).To(Equal(1))
+		})
+
+		It("should keep the secret without an owner reference with the Retain policy", func() {
+			secret := deleteWithPolicy(secretsv1alpha1.DeletionPolicyRetain)
+			Expect(secret).NotTo(BeNil())
+			Expect(secret.OwnerReferences).To(BeEmpty())
+			Expect(secret.Labels).To(HaveKeyWithValue(secretsv1alpha1.ManagedByLabel, resourceName))
+		})
+
+		It("should delete the secret with the Delete policy", func() {
+			Expect(deleteWithPolicy(secretsv1alpha1.DeletionPolicyDelete)).To(BeNil())
+		})
+
+		It("should keep the secret without any ownership metadata with the Orphan policy", func() {
+			secret := deleteWithPolicy(secretsv1alpha1.DeletionPolicyOrphan)
+			Expect(secret).NotTo(BeNil())
+			Expect(secret.OwnerReferences).To(BeEmpty())
+			Expect(secret.Labels).NotTo(HaveKey(secretsv1alpha1.ManagedByLabel))
+			Expect(secret.Data).To(HaveKey("password"))
 		})
 	})
 })
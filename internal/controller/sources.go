@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/secretdata"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/vaultclient"
+)
+
+// sourceRead is a source of a SecretRotation together with the secret read from it
+type sourceRead struct {
+	secretsv1alpha1.SecretSource
+	secret *vault.KVSecret
+}
+
+// secretSources returns the sources of spec. A spec without sources reads its
+// single, unnamed source.
+func secretSources(spec *secretsv1alpha1.SecretDataSpec) []secretsv1alpha1.SecretSource {
+	if len(spec.Sources) > 0 {
+		return spec.Sources
+	}
+	return []secretsv1alpha1.SecretSource{{
+		VaultSource: spec.VaultSource,
+		KeyMapping:  spec.KeyMapping,
+		Encoding:    spec.Encoding,
+	}}
+}
+
+// describeSources returns a human readable location of sources
+func describeSources(sources []secretsv1alpha1.SecretSource) string {
+	descriptions := make([]string, 0, len(sources))
+	for _, source := range sources {
+		descriptions = append(descriptions, describeVaultSource(source.VaultSource))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// sourceError names the source err happened for, if it has a name
+func sourceError(source secretsv1alpha1.SecretSource, err error) error {
+	if source.Name == "" {
+		return err
+	}
+	return fmt.Errorf("source %s: %w", source.Name, err)
+}
+
+// readSources reads every source from Vault, stopping at the first failure.
+// It returns the reason for the VaultReadable condition alongside the error.
+func readSources(ctx context.Context, vaultClient *vault.Client, sources []secretsv1alpha1.SecretSource) ([]sourceRead, string, error) {
+	reads := make([]sourceRead, 0, len(sources))
+	for _, source := range sources {
+		mount, path := vaultSourceLabels(source.VaultSource)
+		readStart := time.Now()
+		secret, err := readVaultSource(ctx, vaultClient, source.VaultSource)
+		vaultReadDuration.WithLabelValues(mount, path).Observe(time.Since(readStart).Seconds())
+		if err != nil {
+			reason := secretsv1alpha1.ReasonVaultReadFailed
+			switch {
+			case errors.Is(err, vault.ErrSecretNotFound):
+				reason = secretsv1alpha1.ReasonSecretNotFound
+			case errors.Is(err, vaultclient.ErrSecretDeleted):
+				reason = secretsv1alpha1.ReasonSecretDeleted
+			}
+			vaultReadErrors.WithLabelValues(mount, path, reason).Inc()
+			return nil, reason, sourceError(source, err)
+		}
+		reads = append(reads, sourceRead{SecretSource: source, secret: secret})
+	}
+	return reads, "", nil
+}
+
+// mergeSources decodes and maps the data of each source and merges it into
+// the data of the target secret, resolving keys set by several sources with
+// policy. owners maps each secret key to the name of the source it came from.
+// It returns the reason for the SecretSynced condition alongside the error.
+func mergeSources(reads []sourceRead, policy secretsv1alpha1.ConflictPolicy) (data map[string]interface{}, owners map[string]string, reason string, err error) {
+	data = make(map[string]interface{})
+	owners = make(map[string]string)
+	for _, read := range reads {
+		values := read.secret.Data
+		if read.Encoding != nil {
+			values, err = secretdata.DecodeBase64(values, read.Encoding.Base64)
+			if err != nil {
+				return nil, nil, secretsv1alpha1.ReasonValueEncodingFailed, sourceError(read.SecretSource, err)
+			}
+		}
+		values, err = mapKeys(values, read.KeyMapping)
+		if err != nil {
+			return nil, nil, secretsv1alpha1.ReasonInvalidKeyMapping, sourceError(read.SecretSource, err)
+		}
+
+		// Walk the keys in order so conflicts are reported deterministically
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if owner, ok := owners[k]; ok {
+				switch policy {
+				case secretsv1alpha1.ConflictPolicyFirstWins:
+					continue
+				case secretsv1alpha1.ConflictPolicyLastWins:
+				default:
+					return nil, nil, secretsv1alpha1.ReasonSourceConflict,
+						fmt.Errorf("sources %s and %s both set secret key %q", owner, read.Name, k)
+				}
+			}
+			data[k] = values[k]
+			owners[k] = read.Name
+		}
+	}
+	return data, owners, "", nil
+}
+
+// sourceStatuses reports what was synced from each source
+func sourceStatuses(reads []sourceRead, owners map[string]string) []secretsv1alpha1.SourceStatus {
+	keys := make(map[string][]string, len(reads))
+	for k, owner := range owners {
+		keys[owner] = append(keys[owner], k)
+	}
+
+	statuses := make([]secretsv1alpha1.SourceStatus, 0, len(reads))
+	for _, read := range reads {
+		sort.Strings(keys[read.Name])
+		statuses = append(statuses, secretsv1alpha1.SourceStatus{
+			Name:          read.Name,
+			Path:          describeVaultSource(read.VaultSource),
+			SyncedVersion: syncedVersion(read.secret.VersionMetadata),
+			Keys:          keys[read.Name],
+		})
+	}
+	return statuses
+}
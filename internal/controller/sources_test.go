@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	vault "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+// testSourceRead returns a read of the source name holding data
+func testSourceRead(name string, data map[string]interface{}, mapping *secretsv1alpha1.KeyMapping) sourceRead {
+	return sourceRead{
+		SecretSource: secretsv1alpha1.SecretSource{Name: name, KeyMapping: mapping},
+		secret:       &vault.KVSecret{Data: data},
+	}
+}
+
+var _ = Describe("mergeSources", func() {
+	var reads []sourceRead
+
+	BeforeEach(func() {
+		reads = []sourceRead{
+			testSourceRead("database", map[string]interface{}{"username": "app", "password": "db-pass"}, nil),
+			testSourceRead("cache", map[string]interface{}{"password": "cache-pass", "host": "cache"}, nil),
+			testSourceRead("defaults", map[string]interface{}{"password": "default-pass", "port": "5432"}, nil),
+		}
+	})
+
+	It("merges sources without shared keys with every policy", func() {
+		reads = []sourceRead{
+			testSourceRead("database", map[string]interface{}{"username": "app"}, nil),
+			testSourceRead("cache", map[string]interface{}{"host": "cache"}, nil),
+		}
+		for _, policy := range []secretsv1alpha1.ConflictPolicy{
+			"", secretsv1alpha1.ConflictPolicyError, secretsv1alpha1.ConflictPolicyFirstWins, secretsv1alpha1.ConflictPolicyLastWins,
+		} {
+			data, owners, _, err := mergeSources(reads, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(map[string]interface{}{"username": "app", "host": "cache"}))
+			Expect(owners).To(Equal(map[string]string{"username": "database", "host": "cache"}))
+		}
+	})
+
+	DescribeTable("fails on a key set by several sources",
+		func(policy secretsv1alpha1.ConflictPolicy) {
+			_, _, reason, err := mergeSources(reads, policy)
+			Expect(reason).To(Equal(secretsv1alpha1.ReasonSourceConflict))
+			Expect(err).To(MatchError(`sources database and cache both set secret key "password"`))
+		},
+		Entry("with the Error policy", secretsv1alpha1.ConflictPolicyError),
+		Entry("without a policy", secretsv1alpha1.ConflictPolicy("")),
+	)
+
+	It("keeps the value of the source listed first with FirstWins", func() {
+		data, owners, _, err := mergeSources(reads, secretsv1alpha1.ConflictPolicyFirstWins)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal(map[string]interface{}{
+			"username": "app", "password": "db-pass", "host": "cache", "port": "5432",
+		}))
+		Expect(owners).To(HaveKeyWithValue("password", "database"))
+		Expect(owners).To(HaveKeyWithValue("port", "defaults"))
+	})
+
+	It("keeps the value of the source listed last with LastWins", func() {
+		data, owners, _, err := mergeSources(reads, secretsv1alpha1.ConflictPolicyLastWins)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal(map[string]interface{}{
+			"username": "app", "password": "default-pass", "host": "cache", "port": "5432",
+		}))
+		Expect(owners).To(HaveKeyWithValue("password", "defaults"))
+		Expect(owners).To(HaveKeyWithValue("username", "database"))
+	})
+
+	It("follows the order of the sources rather than their names", func() {
+		reads[0], reads[2] = reads[2], reads[0]
+
+		data, owners, _, err := mergeSources(reads, secretsv1alpha1.ConflictPolicyFirstWins)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(HaveKeyWithValue("password", "default-pass"))
+		Expect(owners).To(HaveKeyWithValue("password", "defaults"))
+
+		data, owners, _, err = mergeSources(reads, secretsv1alpha1.ConflictPolicyLastWins)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(HaveKeyWithValue("password", "db-pass"))
+		Expect(owners).To(HaveKeyWithValue("password", "database"))
+
+		_, _, _, err = mergeSources(reads, secretsv1alpha1.ConflictPolicyError)
+		Expect(err).To(MatchError(`sources defaults and cache both set secret key "password"`))
+	})
+
+	It("detects duplicate keys after the key mapping of each source", func() {
+		reads = []sourceRead{
+			testSourceRead("database", map[string]interface{}{"password": "db-pass"},
+				&secretsv1alpha1.KeyMapping{Prefix: "db_"}),
+			testSourceRead("cache", map[string]interface{}{"password": "cache-pass"},
+				&secretsv1alpha1.KeyMapping{Rename: map[string]string{"password": "db_password"}}),
+		}
+		_, _, reason, err := mergeSources(reads, secretsv1alpha1.ConflictPolicyError)
+		Expect(reason).To(Equal(secretsv1alpha1.ReasonSourceConflict))
+		Expect(err).To(MatchError(`sources database and cache both set secret key "db_password"`))
+
+		reads[1].KeyMapping = &secretsv1alpha1.KeyMapping{Prefix: "cache_"}
+		data, _, _, err := mergeSources(reads, secretsv1alpha1.ConflictPolicyError)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal(map[string]interface{}{"db_password": "db-pass", "cache_password": "cache-pass"}))
+	})
+
+	It("reports a key mapping error with the name of the source", func() {
+		reads[1].KeyMapping = &secretsv1alpha1.KeyMapping{Rename: map[string]string{"host": "password"}}
+		_, _, reason, err := mergeSources(reads, secretsv1alpha1.ConflictPolicyLastWins)
+		Expect(reason).To(Equal(secretsv1alpha1.ReasonInvalidKeyMapping))
+		Expect(err).To(MatchError(ContainSubstring("source cache: ")))
+	})
+})
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/secretdata"
+)
+
+// renderTemplate renders the templated keys of spec from the secrets read from
+// Vault and returns the data of the target secret. Unless the template
+// merges, only the rendered keys are kept from data.
+func renderTemplate(spec *secretsv1alpha1.SecretDataSpec, reads []sourceRead, data map[string]interface{}) (map[string]interface{}, error) {
+	// A single source is rendered from its raw data, several from the merged
+	// data with each source available by name
+	input := secretdata.Input{Data: data}
+	if len(spec.Sources) == 0 {
+		input.Data = reads[0].secret.Data
+		input.Metadata = templateMetadata(reads[0].VaultSource, reads[0].secret)
+	} else {
+		input.Sources = make(map[string]secretdata.Source, len(reads))
+		for _, read := range reads {
+			input.Sources[read.Name] = secretdata.Source{
+				Data:     read.secret.Data,
+				Metadata: templateMetadata(read.VaultSource, read.secret),
+			}
+		}
+	}
+	rendered, err := secretdata.Render(spec.Template.Data, input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{}, len(data)+len(rendered))
+	if spec.Template.Merge {
+		for k, v := range data {
+			result[k] = v
+		}
+	}
+	for k, v := range rendered {
+		result[k] = v
+	}
+	return result, nil
+}
+
+// templateMetadata describes the Vault secret read for source to templates
+func templateMetadata(source secretsv1alpha1.VaultSource, kvSecret *vault.KVSecret) secretdata.Metadata {
+	mount, path := vaultSourceLabels(source)
+	metadata := secretdata.Metadata{Mount: mount, Path: path}
+	if kvSecret.VersionMetadata != nil {
+		metadata.Version = kvSecret.VersionMetadata.Version
+		metadata.CreatedTime = kvSecret.VersionMetadata.CreatedTime
+	}
+	if kvSecret.Raw != nil {
+		metadata.LeaseID = kvSecret.Raw.LeaseID
+		metadata.LeaseDuration = time.Duration(kvSecret.Raw.LeaseDuration) * time.Second
+	}
+	return metadata
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	vault "github.com/hashicorp/vault/api"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/vaultclient"
+)
+
+const (
+	// vaultAuthRefField indexes SecretRotations by spec.vaultAuthRef
+	vaultAuthRefField = ".spec.vaultAuthRef"
+	// vaultConnectionRefField indexes VaultAuths by spec.vaultConnectionRef
+	vaultConnectionRefField = ".spec.vaultConnectionRef"
+
+	// serviceAccountTokenTTL is the lifetime requested for service account tokens sent to Vault
+	serviceAccountTokenTTL = int64(600)
+)
+
+// vaultClientFor returns the Vault client to use for sr: the operator's shared
+// client, or a cached client for the VaultAuth it references. Any error means
+// the operator is not authenticated for sr.
+func (r *SecretRotationReconciler) vaultClientFor(ctx context.Context, sr *secretsv1alpha1.SecretRotation) (*vault.Client, error) {
+	if sr.Spec.VaultAuthRef == "" {
+		if r.VaultToken != nil {
+			if err := r.VaultToken.Err(); err != nil {
+				return nil, err
+			}
+		}
+		return r.Vault, nil
+	}
+
+	if r.VaultClients == nil {
+		return nil, fmt.Errorf("vaultAuthRef is set but per-connection Vault clients are not enabled")
+	}
+
+	key := types.NamespacedName{Namespace: sr.Namespace, Name: sr.Spec.VaultAuthRef}
+	vaultAuth := &secretsv1alpha1.VaultAuth{}
+	if err := r.Get(ctx, key, vaultAuth); err != nil {
+		if kerrors.IsNotFound(err) {
+			r.VaultClients.Delete(ctx, key.String())
+		}
+		return nil, fmt.Errorf("failed to get VaultAuth %s: %w", key.Name, err)
+	}
+
+	fingerprint := vaultAuth.ResourceVersion
+	conn := vaultclient.Connection{}
+	if ref := vaultAuth.Spec.VaultConnectionRef; ref != "" {
+		vaultConn := &secretsv1alpha1.VaultConnection{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: sr.Namespace, Name: ref}, vaultConn); err != nil {
+			return nil, fmt.Errorf("failed to get VaultConnection %s: %w", ref, err)
+		}
+		fingerprint += "/" + vaultConn.ResourceVersion
+		conn = vaultclient.Connection{
+			Address:       vaultConn.Spec.Address,
+			CACert:        []byte(vaultConn.Spec.CABundle),
+			TLSServerName: vaultConn.Spec.TLSServerName,
+			Namespace:     vaultConn.Spec.Namespace,
+		}
+	}
+
+	// The operator may request tokens for any service account, so only use those that opted in
+	if vaultAuth.Spec.Method == secretsv1alpha1.VaultAuthMethodKubernetes && vaultAuth.Spec.Kubernetes != nil {
+		serviceAccount, err := r.vaultAuthServiceAccount(ctx, vaultAuth)
+		if err != nil {
+			r.VaultClients.Delete(ctx, key.String())
+			return nil, err
+		}
+		fingerprint += "/" + serviceAccount.ResourceVersion
+	}
+
+	auth, err := r.authenticatorFor(vaultAuth)
+	if err != nil {
+		return nil, err
+	}
+	return r.VaultClients.Get(ctx, key.String(), fingerprint, conn, auth)
+}
+
+// releaseVaultClient drops the cached client for the VaultAuth sr references,
+// revoking its token, once no other SecretRotation uses it
+func (r *SecretRotationReconciler) releaseVaultClient(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation) {
+	if sr.Spec.VaultAuthRef == "" || r.VaultClients == nil {
+		return
+	}
+	var users secretsv1alpha1.SecretRotationList
+	if err := r.List(ctx, &users, client.InNamespace(sr.Namespace),
+		client.MatchingFields{vaultAuthRefField: sr.Spec.VaultAuthRef}); err != nil {
+		log.Error(err, "failed to list SecretRotations, keeping the cached Vault client", "vaultAuth", sr.Spec.VaultAuthRef)
+		return
+	}
+	for _, other := range users.Items {
+		if other.UID != sr.UID && other.DeletionTimestamp == nil {
+			return
+		}
+	}
+	r.VaultClients.Delete(ctx, types.NamespacedName{Namespace: sr.Namespace, Name: sr.Spec.VaultAuthRef}.String())
+}
+
+// authenticatorFor builds the Authenticator described by a VaultAuth
+func (r *SecretRotationReconciler) authenticatorFor(vaultAuth *secretsv1alpha1.VaultAuth) (vaultclient.Authenticator, error) {
+	switch vaultAuth.Spec.Method {
+	case secretsv1alpha1.VaultAuthMethodKubernetes:
+		if vaultAuth.Spec.Kubernetes == nil {
+			return nil, fmt.Errorf("VaultAuth %s: spec.kubernetes is required for method kubernetes", vaultAuth.Name)
+		}
+		k8sAuth := vaultAuth.Spec.Kubernetes
+		serviceAccount := vaultAuthServiceAccountName(vaultAuth)
+		return &vaultclient.KubernetesAuth{
+			Role:      k8sAuth.Role,
+			MountPath: vaultAuth.Spec.MountPath,
+			JWT: func(ctx context.Context) (string, error) {
+				return r.serviceAccountToken(ctx, vaultAuth.Namespace, serviceAccount, k8sAuth.Audiences)
+			},
+		}, nil
+	case secretsv1alpha1.VaultAuthMethodAppRole:
+		if vaultAuth.Spec.AppRole == nil {
+			return nil, fmt.Errorf("VaultAuth %s: spec.appRole is required for method appRole", vaultAuth.Name)
+		}
+		return &vaultclient.AppRoleAuth{
+			MountPath: vaultAuth.Spec.MountPath,
+			SecretRef: types.NamespacedName{Namespace: vaultAuth.Namespace, Name: vaultAuth.Spec.AppRole.SecretRef},
+			Reader:    r.Client,
+		}, nil
+	default:
+		return nil, fmt.Errorf("VaultAuth %s: unsupported method %q", vaultAuth.Name, vaultAuth.Spec.Method)
+	}
+}
+
+// vaultAuthServiceAccountName returns the service account a kubernetes VaultAuth logs in as
+func vaultAuthServiceAccountName(vaultAuth *secretsv1alpha1.VaultAuth) string {
+	if vaultAuth.Spec.Kubernetes.ServiceAccount != "" {
+		return vaultAuth.Spec.Kubernetes.ServiceAccount
+	}
+	return "default"
+}
+
+// vaultAuthServiceAccount returns the service account of a kubernetes
+// VaultAuth, or an error unless it allows Vault logins with its tokens
+func (r *SecretRotationReconciler) vaultAuthServiceAccount(ctx context.Context, vaultAuth *secretsv1alpha1.VaultAuth) (*corev1.ServiceAccount, error) {
+	name := vaultAuthServiceAccountName(vaultAuth)
+	serviceAccount := &corev1.ServiceAccount{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: vaultAuth.Namespace, Name: name}, serviceAccount); err != nil {
+		return nil, fmt.Errorf("failed to get service account %s for VaultAuth %s: %w", name, vaultAuth.Name, err)
+	}
+	if serviceAccount.Annotations[secretsv1alpha1.VaultAuthServiceAccountAnnotation] != "true" {
+		return nil, fmt.Errorf("service account %s is not annotated with %s=true, so VaultAuth %s may not log in with its tokens",
+			name, secretsv1alpha1.VaultAuthServiceAccountAnnotation, vaultAuth.Name)
+	}
+	return serviceAccount, nil
+}
+
+// serviceAccountToken requests a short-lived token for a service account
+func (r *SecretRotationReconciler) serviceAccountToken(ctx context.Context, namespace, name string, audiences []string) (string, error) {
+	serviceAccount := &corev1.ServiceAccount{}
+	serviceAccount.Namespace = namespace
+	serviceAccount.Name = name
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         audiences,
+			ExpirationSeconds: ptr.To(serviceAccountTokenTTL),
+		},
+	}
+	if err := r.SubResource("token").Create(ctx, serviceAccount, tokenRequest); err != nil {
+		return "", fmt.Errorf("failed to request token for service account %s/%s: %w", namespace, name, err)
+	}
+	return tokenRequest.Status.Token, nil
+}
+
+// secretRotationsForVaultAuth maps a VaultAuth to the SecretRotations using it
+func (r *SecretRotationReconciler) secretRotationsForVaultAuth(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.secretRotationsReferencing(ctx, obj.GetNamespace(), obj.GetName())
+}
+
+// secretRotationsForVaultConnection maps a VaultConnection to the
+// SecretRotations using a VaultAuth that references it
+func (r *SecretRotationReconciler) secretRotationsForVaultConnection(ctx context.Context, obj client.Object) []reconcile.Request {
+	var vaultAuths secretsv1alpha1.VaultAuthList
+	if err := r.List(ctx, &vaultAuths, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{vaultConnectionRefField: obj.GetName()}); err != nil {
+		r.Log.Error(err, "failed to list VaultAuths", "vaultConnection", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, vaultAuth := range vaultAuths.Items {
+		requests = append(requests, r.secretRotationsReferencing(ctx, vaultAuth.Namespace, vaultAuth.Name)...)
+	}
+	return requests
+}
+
+func (r *SecretRotationReconciler) secretRotationsReferencing(ctx context.Context, namespace, vaultAuth string) []reconcile.Request {
+	var secretRotations secretsv1alpha1.SecretRotationList
+	if err := r.List(ctx, &secretRotations, client.InNamespace(namespace),
+		client.MatchingFields{vaultAuthRefField: vaultAuth}); err != nil {
+		r.Log.Error(err, "failed to list SecretRotations", "vaultAuth", vaultAuth)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(secretRotations.Items))
+	for _, sr := range secretRotations.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: sr.Namespace, Name: sr.Name},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/vaultclient"
+)
+
+// vaultPathField indexes SecretRotations by the Vault data path they read,
+// as reported in the data_path of Vault events
+const vaultPathField = ".spec.vaultDataPath"
+
+// vaultDataPathKeys returns the vaultPathField index values of a SecretRotation,
+// one per source. Only KV sources read through the shared Vault client are
+// indexed, since events are only received from that client.
+func vaultDataPathKeys(obj client.Object) []string {
+	sr := obj.(*secretsv1alpha1.SecretRotation)
+	if sr.Spec.VaultAuthRef != "" {
+		return nil
+	}
+
+	var keys []string
+	for _, source := range secretSources(&sr.Spec.SecretDataSpec) {
+		if key := vaultDataPath(source.VaultSource); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// vaultDataPath returns the data path Vault reports in events for writes to
+// source, or "" if source is not a KV secret
+func vaultDataPath(source secretsv1alpha1.VaultSource) string {
+	mount := strings.Trim(source.Mount, "/")
+	path := strings.Trim(source.Path, "/")
+	switch source.Engine {
+	case "":
+		return strings.Trim(source.VaultPath, "/")
+	case secretsv1alpha1.SecretEngineKVv1:
+		return mount + "/" + path
+	case secretsv1alpha1.SecretEngineKVv2:
+		return mount + "/data/" + path
+	}
+	return ""
+}
+
+// secretRotationsForVaultEvent maps a Vault event to the SecretRotations
+// reading the written path, in any namespace
+func (r *SecretRotationReconciler) secretRotationsForVaultEvent(ctx context.Context, event vaultclient.Event) []reconcile.Request {
+	var secretRotations secretsv1alpha1.SecretRotationList
+	if err := r.List(ctx, &secretRotations, client.MatchingFields{vaultPathField: event.DataPath}); err != nil {
+		r.Log.Error(err, "failed to list SecretRotations", "vaultPath", event.DataPath)
+		return nil
+	}
+	return secretRotationRequests(secretRotations.Items)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+	"github.com/Amogha-rao/secret-rotator-operator/internal/vaultclient"
+)
+
+// readVaultSource reads the secret described by source
+func readVaultSource(ctx context.Context, vaultClient *vault.Client, source secretsv1alpha1.VaultSource) (*vault.KVSecret, error) {
+	switch source.Engine {
+	case secretsv1alpha1.SecretEngineKVv1:
+		return vaultclient.ReadKVv1(ctx, vaultClient, source.Mount, source.Path)
+	case secretsv1alpha1.SecretEngineKVv2:
+		version := 0
+		if source.Version != nil {
+			version = *source.Version
+		}
+		return vaultclient.ReadKVv2(ctx, vaultClient, source.Mount, source.Path, version)
+	case secretsv1alpha1.SecretEngineDatabase:
+		secret, err := vaultclient.ReadDatabaseCredentials(ctx, vaultClient, source.Mount, source.Role)
+		if err != nil {
+			return nil, err
+		}
+		return &vault.KVSecret{Data: secret.Data, Raw: secret}, nil
+	case secretsv1alpha1.SecretEnginePKI:
+		cert, err := vaultclient.IssueCertificate(ctx, vaultClient, source.Mount, source.Role, certificateRequest(source.PKI))
+		if err != nil {
+			return nil, err
+		}
+		return &vault.KVSecret{
+			Data: map[string]interface{}{
+				corev1.TLSCertKey:       cert.FullChain(),
+				corev1.TLSPrivateKeyKey: cert.PrivateKey,
+				tlsCAKey:                cert.CA(),
+			},
+			Raw: cert.Raw,
+		}, nil
+	case "":
+		return readVaultPath(ctx, vaultClient, source.VaultPath)
+	default:
+		return nil, fmt.Errorf("unsupported secrets engine %q", source.Engine)
+	}
+}
+
+// readVaultPath reads a raw vaultPath. The engine is unknown, so data nested
+// under "data" is assumed to come from KV v2 and anything else from KV v1.
+func readVaultPath(ctx context.Context, vaultClient *vault.Client, path string) (*vault.KVSecret, error) {
+	secret, err := vaultClient.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("%w: at %s", vault.ErrSecretNotFound, path)
+	}
+
+	// Vault KV v2 stores actual data under "data" key
+	data, ok := secret.Data["data"].(map[string]interface{})
+	if !ok {
+		data = secret.Data // fallback if not KV v2
+	}
+	return &vault.KVSecret{Data: data, Raw: secret}, nil
+}
+
+// vaultSourceLabels returns the mount and path metrics about source are labelled with
+func vaultSourceLabels(source secretsv1alpha1.VaultSource) (string, string) {
+	mount := strings.Trim(source.Mount, "/")
+	switch source.Engine {
+	case "":
+		return "", source.VaultPath
+	case secretsv1alpha1.SecretEngineDatabase:
+		if mount == "" {
+			mount = "database"
+		}
+		return mount, "creds/" + source.Role
+	case secretsv1alpha1.SecretEnginePKI:
+		if mount == "" {
+			mount = "pki"
+		}
+		return mount, "issue/" + source.Role
+	}
+	return mount, source.Path
+}
+
+// targetSecretType returns the type of the Secret source is written to
+func targetSecretType(source secretsv1alpha1.VaultSource) corev1.SecretType {
+	if source.Engine == secretsv1alpha1.SecretEnginePKI {
+		return corev1.SecretTypeTLS
+	}
+	return corev1.SecretTypeOpaque
+}
+
+// describeVaultSource returns a human readable location of source
+func describeVaultSource(source secretsv1alpha1.VaultSource) string {
+	switch source.Engine {
+	case "":
+		return source.VaultPath
+	case secretsv1alpha1.SecretEngineDatabase:
+		return fmt.Sprintf("%s (database)", vaultclient.DatabaseCredentialsPath(source.Mount, source.Role))
+	case secretsv1alpha1.SecretEnginePKI:
+		return fmt.Sprintf("%s (pki)", vaultclient.PKIIssuePath(source.Mount, source.Role))
+	}
+	description := fmt.Sprintf("%s/%s (%s)", source.Mount, source.Path, source.Engine)
+	if source.Version != nil {
+		description += fmt.Sprintf(" version %d", *source.Version)
+	}
+	return description
+}
+
+// syncedVersion converts KV v2 version metadata for the SecretRotation status
+func syncedVersion(metadata *vault.KVVersionMetadata) *secretsv1alpha1.VaultSecretVersion {
+	if metadata == nil {
+		return nil
+	}
+	version := &secretsv1alpha1.VaultSecretVersion{
+		Version:   metadata.Version,
+		Destroyed: metadata.Destroyed,
+	}
+	if !metadata.CreatedTime.IsZero() {
+		version.CreatedTime = &metav1.Time{Time: metadata.CreatedTime}
+	}
+	if !metadata.DeletionTime.IsZero() {
+		version.DeletionTime = &metav1.Time{Time: metadata.DeletionTime}
+	}
+	return version
+}
@@ -0,0 +1,395 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+// workloadSelectionField indexes SecretRotations that select or discover workloads
+const workloadSelectionField = ".spec.workloadSelection"
+
+// workloadSelectionKeys returns the workloadSelectionField index values of sr
+func workloadSelectionKeys(obj client.Object) []string {
+	sr := obj.(*secretsv1alpha1.SecretRotation)
+	if sr.Spec.WorkloadSelector == nil && !sr.Spec.DiscoverWorkloads {
+		return nil
+	}
+	return []string{"true"}
+}
+
+// workloadKind returns the kind of a watched workload, or "" for other objects
+func workloadKind(obj client.Object) string {
+	switch obj.(type) {
+	case *appsv1.Deployment:
+		return "Deployment"
+	case *appsv1.StatefulSet:
+		return "StatefulSet"
+	case *appsv1.DaemonSet:
+		return "DaemonSet"
+	case *appsv1.ReplicaSet:
+		return "ReplicaSet"
+	case *batchv1.CronJob:
+		return "CronJob"
+	case *metav1.PartialObjectMetadata:
+		return obj.GetObjectKind().GroupVersionKind().Kind
+	}
+	return ""
+}
+
+// podTemplate returns the pod template of a supported workload, or nil for other objects
+func podTemplate(obj client.Object) *corev1.PodTemplateSpec {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return &workload.Spec.Template
+	case *appsv1.StatefulSet:
+		return &workload.Spec.Template
+	case *appsv1.DaemonSet:
+		return &workload.Spec.Template
+	case *appsv1.ReplicaSet:
+		return &workload.Spec.Template
+	case *batchv1.CronJob:
+		return &workload.Spec.JobTemplate.Spec.Template
+	}
+	return nil
+}
+
+// workloadStatusKey returns how workload is listed in the status of sr
+func workloadStatusKey(sr *secretsv1alpha1.SecretRotation, workload secretsv1alpha1.WorkloadReference) string {
+	if workload.Namespace != "" && workload.Namespace != sr.Namespace {
+		return fmt.Sprintf("%s/%s/%s", workload.Namespace, workload.Kind, workload.Name)
+	}
+	return fmt.Sprintf("%s/%s", workload.Kind, workload.Name)
+}
+
+// builtinWorkloads are the kinds a WorkloadReference may name without apiVersion
+var builtinWorkloads = map[string]schema.GroupVersionKind{
+	"deployment":  appsv1.SchemeGroupVersion.WithKind("Deployment"),
+	"statefulset": appsv1.SchemeGroupVersion.WithKind("StatefulSet"),
+	"daemonset":   appsv1.SchemeGroupVersion.WithKind("DaemonSet"),
+	"replicaset":  appsv1.SchemeGroupVersion.WithKind("ReplicaSet"),
+	"cronjob":     batchv1.SchemeGroupVersion.WithKind("CronJob"),
+}
+
+// workloadGVK returns the group, version and kind of workload
+func workloadGVK(workload secretsv1alpha1.WorkloadReference) (schema.GroupVersionKind, error) {
+	if workload.APIVersion == "" {
+		gvk, ok := builtinWorkloads[strings.ToLower(workload.Kind)]
+		if !ok {
+			return schema.GroupVersionKind{}, fmt.Errorf("apiVersion is required for workload kind %s", workload.Kind)
+		}
+		return gvk, nil
+	}
+	gv, err := schema.ParseGroupVersion(workload.APIVersion)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	if gvk, ok := builtinWorkloads[strings.ToLower(workload.Kind)]; ok && gvk.GroupVersion() == gv {
+		return gvk, nil
+	}
+	return gv.WithKind(workload.Kind), nil
+}
+
+// podTemplatePath returns the fields leading to the pod template of workload
+func podTemplatePath(workload secretsv1alpha1.WorkloadReference) []string {
+	if workload.PodTemplatePath != "" {
+		return strings.Split(workload.PodTemplatePath, ".")
+	}
+	if strings.EqualFold(workload.Kind, "CronJob") {
+		return []string{"spec", "jobTemplate", "spec", "template"}
+	}
+	return []string{"spec", "template"}
+}
+
+// workloadObject returns an empty object to read workload into. The apps
+// kinds are typed, so they are served from the cache; other kinds are
+// unstructured.
+func workloadObject(workload secretsv1alpha1.WorkloadReference, namespace string) (client.Object, error) {
+	gvk, err := workloadGVK(workload)
+	if err != nil {
+		return nil, err
+	}
+	var obj client.Object
+	switch gvk {
+	case builtinWorkloads["deployment"]:
+		obj = &appsv1.Deployment{}
+	case builtinWorkloads["statefulset"]:
+		obj = &appsv1.StatefulSet{}
+	case builtinWorkloads["daemonset"]:
+		obj = &appsv1.DaemonSet{}
+	case builtinWorkloads["replicaset"]:
+		obj = &appsv1.ReplicaSet{}
+	case builtinWorkloads["cronjob"]:
+		obj = &batchv1.CronJob{}
+	default:
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		obj = u
+	}
+	obj.SetNamespace(namespace)
+	obj.SetName(workload.Name)
+	return obj, nil
+}
+
+// podTemplateAnnotations returns the annotations of the pod template at path in obj
+func podTemplateAnnotations(obj client.Object, path []string) (map[string]string, error) {
+	var content map[string]interface{}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		content = u.Object
+	} else {
+		var err error
+		if content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+			return nil, err
+		}
+	}
+	if _, found, err := unstructured.NestedMap(content, path...); err != nil || !found {
+		return nil, fmt.Errorf("workload %s has no pod template at %s", obj.GetName(), strings.Join(path, "."))
+	}
+	annotations, _, err := unstructured.NestedStringMap(content, slices.Concat(path, []string{"metadata", "annotations"})...)
+	return annotations, err
+}
+
+// usesSecret reports whether pods created from spec read the secret name
+// through env, envFrom, a secret volume or a projected volume
+func usesSecret(spec *corev1.PodSpec, name string) bool {
+	for _, volume := range spec.Volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == name {
+			return true
+		}
+		if volume.Projected == nil {
+			continue
+		}
+		for _, projection := range volume.Projected.Sources {
+			if projection.Secret != nil && projection.Secret.Name == name {
+				return true
+			}
+		}
+	}
+
+	containers := slices.Concat(spec.InitContainers, spec.Containers)
+	for _, container := range spec.EphemeralContainers {
+		containers = append(containers, corev1.Container{Env: container.Env, EnvFrom: container.EnvFrom})
+	}
+	for _, container := range containers {
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == name {
+				return true
+			}
+		}
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil && envFrom.SecretRef.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// listWorkloads lists the supported workloads matching opts. ReplicaSets
+// managed by a Deployment are skipped; the Deployment is restarted instead.
+func (r *SecretRotationReconciler) listWorkloads(ctx context.Context, opts ...client.ListOption) ([]client.Object, error) {
+	var workloads []client.Object
+	for _, list := range []client.ObjectList{
+		&appsv1.DeploymentList{}, &appsv1.StatefulSetList{}, &appsv1.DaemonSetList{}, &appsv1.ReplicaSetList{},
+		&batchv1.CronJobList{},
+	} {
+		if err := r.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			obj := item.(client.Object)
+			if _, ok := obj.(*appsv1.ReplicaSet); ok && metav1.GetControllerOf(obj) != nil {
+				continue
+			}
+			workloads = append(workloads, obj)
+		}
+	}
+	return workloads, nil
+}
+
+// selectedNamespaces returns the namespaces workloads are selected in, or
+// nil when selector selects every namespace. Unless the operator allows
+// cross-namespace workloads, only the namespace of sr can be selected.
+func (r *SecretRotationReconciler) selectedNamespaces(ctx context.Context, sr *secretsv1alpha1.SecretRotation, selector *metav1.LabelSelector) (sets.Set[string], error) {
+	if selector == nil {
+		return sets.New(sr.Namespace), nil
+	}
+	namespaceSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if !r.CrossNamespaceWorkloads {
+		var namespace corev1.Namespace
+		if err := r.Get(ctx, client.ObjectKey{Name: sr.Namespace}, &namespace); err != nil {
+			return nil, err
+		}
+		if !namespaceSelector.Matches(labels.Set(namespace.Labels)) {
+			return sets.New[string](), nil
+		}
+		return sets.New(sr.Namespace), nil
+	}
+	if namespaceSelector.Empty() {
+		return nil, nil
+	}
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+		return nil, err
+	}
+	selected := sets.New[string]()
+	for _, ns := range namespaces.Items {
+		selected.Insert(ns.Name)
+	}
+	return selected, nil
+}
+
+// discoverWorkloads returns the workloads matching the workload selector of
+// sr and, with discoverWorkloads set, those using its target secret
+func (r *SecretRotationReconciler) discoverWorkloads(ctx context.Context, sr *secretsv1alpha1.SecretRotation) ([]secretsv1alpha1.WorkloadReference, error) {
+	found := make(map[string]secretsv1alpha1.WorkloadReference)
+	add := func(obj client.Object) {
+		kind := workloadKind(obj)
+		found[workloadIndexKey(kind, obj.GetNamespace(), obj.GetName())] = secretsv1alpha1.WorkloadReference{
+			Kind: kind, Name: obj.GetName(), Namespace: obj.GetNamespace(),
+		}
+	}
+
+	if selector := sr.Spec.WorkloadSelector; selector != nil {
+		labelSelector, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		namespaces, err := r.selectedNamespaces(ctx, sr, selector.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		opts := []client.ListOption{client.MatchingLabelsSelector{Selector: labelSelector}}
+		if namespaces.Len() == 1 {
+			opts = append(opts, client.InNamespace(sets.List(namespaces)[0]))
+		}
+		var workloads []client.Object
+		if namespaces == nil || namespaces.Len() > 0 {
+			if workloads, err = r.listWorkloads(ctx, opts...); err != nil {
+				return nil, err
+			}
+		}
+		for _, obj := range workloads {
+			if namespaces != nil && !namespaces.Has(obj.GetNamespace()) {
+				continue
+			}
+			if len(selector.Kinds) > 0 && !slices.Contains(selector.Kinds, secretsv1alpha1.WorkloadKind(workloadKind(obj))) {
+				continue
+			}
+			add(obj)
+		}
+	}
+
+	// Pods can only use secrets from their own namespace
+	if sr.Spec.DiscoverWorkloads {
+		workloads, err := r.listWorkloads(ctx, client.InNamespace(sr.Namespace))
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range workloads {
+			if usesSecret(&podTemplate(obj).Spec, sr.Spec.TargetSecret) {
+				add(obj)
+			}
+		}
+	}
+
+	// Workloads listed in targetWorkloads are not reported twice
+	for _, workload := range sr.Spec.TargetWorkloads {
+		namespace := workload.Namespace
+		if namespace == "" {
+			namespace = sr.Namespace
+		}
+		delete(found, workloadIndexKey(workload.Kind, namespace, workload.Name))
+	}
+
+	keys := make([]string, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	discovered := make([]secretsv1alpha1.WorkloadReference, 0, len(keys))
+	for _, key := range keys {
+		discovered = append(discovered, found[key])
+	}
+	return discovered, nil
+}
+
+// resolveWorkloads updates the discovered workloads of sr and returns every
+// workload it targets. When discovery fails the workloads discovered before
+// are kept, so a failed list does not skip restarts.
+func (r *SecretRotationReconciler) resolveWorkloads(ctx context.Context, log logr.Logger, sr *secretsv1alpha1.SecretRotation) []secretsv1alpha1.WorkloadReference {
+	if sr.Spec.WorkloadSelector == nil && !sr.Spec.DiscoverWorkloads {
+		sr.Status.DiscoveredWorkloads = nil
+		return sr.Spec.TargetWorkloads
+	}
+	discovered, err := r.discoverWorkloads(ctx, sr)
+	if err != nil {
+		log.Error(err, "failed to discover workloads, using the workloads discovered before")
+	} else {
+		sr.Status.DiscoveredWorkloads = discovered
+	}
+	return slices.Concat(sr.Spec.TargetWorkloads, sr.Status.DiscoveredWorkloads)
+}
+
+// selectsWorkload reports whether obj may be targeted through the workload
+// selector or discovery of sr. Namespace selectors are not evaluated; a
+// needless reconcile is cheap.
+func selectsWorkload(sr *secretsv1alpha1.SecretRotation, obj client.Object) bool {
+	if sr.Spec.DiscoverWorkloads && obj.GetNamespace() == sr.Namespace {
+		if template := podTemplate(obj); template != nil && usesSecret(&template.Spec, sr.Spec.TargetSecret) {
+			return true
+		}
+	}
+	selector := sr.Spec.WorkloadSelector
+	if selector == nil {
+		return false
+	}
+	if selector.NamespaceSelector == nil && obj.GetNamespace() != sr.Namespace {
+		return false
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+	if err != nil {
+		return false
+	}
+	return labelSelector.Matches(labels.Set(obj.GetLabels()))
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/Amogha-rao/secret-rotator-operator/api/v1alpha1"
+)
+
+var _ = Describe("discoverWorkloads", func() {
+	var r *SecretRotationReconciler
+	var sr *secretsv1alpha1.SecretRotation
+
+	BeforeEach(func() {
+		objects := []client.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tier": "app"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"tier": "app"}}},
+		}
+		for _, namespace := range []string{"team-a", "team-b"} {
+			objects = append(objects, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Name: "api", Namespace: namespace, Labels: map[string]string{"app": "api"},
+			}})
+		}
+		r = &SecretRotationReconciler{
+			Client: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(objects...).Build(),
+		}
+		sr = &secretsv1alpha1.SecretRotation{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a"}}
+		sr.Spec.WorkloadSelector = &secretsv1alpha1.WorkloadSelector{
+			LabelSelector:     metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			NamespaceSelector: &metav1.LabelSelector{},
+		}
+	})
+
+	It("keeps a namespace selector to the namespace of the SecretRotation by default", func() {
+		workloads, err := r.discoverWorkloads(context.Background(), sr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workloads).To(Equal([]secretsv1alpha1.WorkloadReference{
+			{Kind: "Deployment", Name: "api", Namespace: "team-a"},
+		}))
+	})
+
+	It("selects nothing when the namespace of the SecretRotation does not match", func() {
+		sr.Spec.WorkloadSelector.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "data"}}
+		workloads, err := r.discoverWorkloads(context.Background(), sr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workloads).To(BeEmpty())
+	})
+
+	It("selects workloads in other namespaces when cross-namespace workloads are allowed", func() {
+		r.CrossNamespaceWorkloads = true
+		workloads, err := r.discoverWorkloads(context.Background(), sr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workloads).To(Equal([]secretsv1alpha1.WorkloadReference{
+			{Kind: "Deployment", Name: "api", Namespace: "team-a"},
+			{Kind: "Deployment", Name: "api", Namespace: "team-b"},
+		}))
+	})
+})
@@ -0,0 +1,185 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rollout reports whether a workload finished rolling out its
+// current pod template, so restarts can be gated on the health of the
+// workloads restarted before.
+package rollout
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// State is the rollout state of a workload
+type State string
+
+const (
+	// Progressing means pods of the current pod template are still starting
+	Progressing State = "Progressing"
+	// Complete means every pod runs the current pod template and is available
+	Complete State = "Complete"
+	// Failed means the workload controller gave up on the rollout
+	Failed State = "Failed"
+)
+
+// Status returns the rollout state of obj and a message explaining it.
+// Kinds it does not know are judged by their status.observedGeneration,
+// phase, conditions and replica counts, whichever they report.
+func Status(obj client.Object) (State, string) {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return deploymentStatus(workload)
+	case *appsv1.StatefulSet:
+		return statefulSetStatus(workload)
+	case *appsv1.DaemonSet:
+		return daemonSetStatus(workload)
+	case *appsv1.ReplicaSet:
+		// ReplicaSets do not replace running pods when their template changes
+		if workload.Status.ObservedGeneration < workload.Generation {
+			return Progressing, "waiting for the ReplicaSet controller to observe the change"
+		}
+		return Complete, "ReplicaSet is up to date"
+	case *batchv1.CronJob:
+		// The next job runs the new template; there is nothing to wait for
+		return Complete, "CronJob is up to date"
+	case *unstructured.Unstructured:
+		return unstructuredStatus(workload)
+	}
+	return Complete, ""
+}
+
+func deploymentStatus(d *appsv1.Deployment) (State, string) {
+	if d.Status.ObservedGeneration < d.Generation {
+		return Progressing, "waiting for the Deployment controller to observe the change"
+	}
+	for _, condition := range d.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			return Failed, fmt.Sprintf("Deployment %s exceeded its progress deadline", d.Name)
+		}
+	}
+	replicas := replicasOrDefault(d.Spec.Replicas)
+	switch {
+	case d.Status.UpdatedReplicas < replicas:
+		return Progressing, fmt.Sprintf("%d of %d updated replicas", d.Status.UpdatedReplicas, replicas)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		return Progressing, fmt.Sprintf("%d old replicas pending termination", d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		return Progressing, fmt.Sprintf("%d of %d updated replicas available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas)
+	}
+	return Complete, fmt.Sprintf("%d replicas available", d.Status.AvailableReplicas)
+}
+
+func statefulSetStatus(s *appsv1.StatefulSet) (State, string) {
+	if s.Status.ObservedGeneration < s.Generation {
+		return Progressing, "waiting for the StatefulSet controller to observe the change"
+	}
+	// Pods of an OnDelete StatefulSet are only replaced when deleted by hand
+	if s.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return Complete, "StatefulSet uses the OnDelete update strategy"
+	}
+	replicas := replicasOrDefault(s.Spec.Replicas)
+	if partition := s.Spec.UpdateStrategy.RollingUpdate; partition != nil && partition.Partition != nil {
+		replicas -= *partition.Partition
+	}
+	switch {
+	case s.Status.UpdatedReplicas < replicas:
+		return Progressing, fmt.Sprintf("%d of %d updated replicas", s.Status.UpdatedReplicas, replicas)
+	case s.Status.ReadyReplicas < replicasOrDefault(s.Spec.Replicas):
+		return Progressing, fmt.Sprintf("%d of %d replicas ready", s.Status.ReadyReplicas, replicasOrDefault(s.Spec.Replicas))
+	}
+	return Complete, fmt.Sprintf("%d replicas ready", s.Status.ReadyReplicas)
+}
+
+func daemonSetStatus(d *appsv1.DaemonSet) (State, string) {
+	if d.Status.ObservedGeneration < d.Generation {
+		return Progressing, "waiting for the DaemonSet controller to observe the change"
+	}
+	if d.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+		return Complete, "DaemonSet uses the OnDelete update strategy"
+	}
+	desired := d.Status.DesiredNumberScheduled
+	switch {
+	case d.Status.UpdatedNumberScheduled < desired:
+		return Progressing, fmt.Sprintf("%d of %d updated pods scheduled", d.Status.UpdatedNumberScheduled, desired)
+	case d.Status.NumberAvailable < desired:
+		return Progressing, fmt.Sprintf("%d of %d pods available", d.Status.NumberAvailable, desired)
+	}
+	return Complete, fmt.Sprintf("%d pods available", d.Status.NumberAvailable)
+}
+
+func unstructuredStatus(u *unstructured.Unstructured) (State, string) {
+	kind := u.GetKind()
+	observed, found, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+	if found && observed < u.GetGeneration() {
+		return Progressing, fmt.Sprintf("waiting for the %s controller to observe the change", kind)
+	}
+
+	// Argo Rollouts report a phase
+	switch phase, _, _ := unstructured.NestedString(u.Object, "status", "phase"); phase {
+	case "Degraded", "Failed":
+		return Failed, fmt.Sprintf("%s is %s", kind, phase)
+	case "Progressing", "Paused":
+		return Progressing, fmt.Sprintf("%s is %s", kind, phase)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _ := condition["type"].(string)
+		status, _ := condition["status"].(string)
+		reason, _ := condition["reason"].(string)
+		switch {
+		case conditionType == "Progressing" && reason == "ProgressDeadlineExceeded":
+			return Failed, fmt.Sprintf("%s exceeded its progress deadline", kind)
+		case (conditionType == "Ready" || conditionType == "Available") && status != string(corev1.ConditionTrue):
+			return Progressing, fmt.Sprintf("%s is not %s", kind, conditionType)
+		}
+	}
+
+	replicas, found, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+	if !found {
+		return Complete, fmt.Sprintf("%s is up to date", kind)
+	}
+	updated, hasUpdated, _ := unstructured.NestedInt64(u.Object, "status", "updatedReplicas")
+	available, found, _ := unstructured.NestedInt64(u.Object, "status", "availableReplicas")
+	if !found {
+		available, _, _ = unstructured.NestedInt64(u.Object, "status", "readyReplicas")
+	}
+	switch {
+	case hasUpdated && updated < replicas:
+		return Progressing, fmt.Sprintf("%d of %d updated replicas", updated, replicas)
+	case available < replicas:
+		return Progressing, fmt.Sprintf("%d of %d replicas available", available, replicas)
+	}
+	return Complete, fmt.Sprintf("%d replicas available", available)
+}
+
+// replicasOrDefault returns the desired replicas, which default to 1
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
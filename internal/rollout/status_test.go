@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stateOf returns the rollout state of obj without its message
+func stateOf(obj client.Object) State {
+	state, _ := Status(obj)
+	return state
+}
+
+var _ = Describe("Status", func() {
+	deployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3)},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				Replicas:           3,
+				UpdatedReplicas:    3,
+				AvailableReplicas:  3,
+			},
+		}
+	}
+
+	It("waits until a Deployment replaced and made available every replica", func() {
+		d := deployment()
+		Expect(stateOf(d)).To(Equal(Complete))
+
+		d.Status.ObservedGeneration = 1
+		Expect(stateOf(d)).To(Equal(Progressing))
+
+		d = deployment()
+		d.Status.UpdatedReplicas = 2
+		Expect(stateOf(d)).To(Equal(Progressing))
+
+		d = deployment()
+		d.Status.Replicas = 4
+		Expect(stateOf(d)).To(Equal(Progressing))
+
+		d = deployment()
+		d.Status.AvailableReplicas = 2
+		state, message := Status(d)
+		Expect(state).To(Equal(Progressing))
+		Expect(message).To(Equal("2 of 3 updated replicas available"))
+	})
+
+	It("fails a Deployment past its progress deadline", func() {
+		d := deployment()
+		d.Status.UpdatedReplicas = 1
+		d.Status.Conditions = []appsv1.DeploymentCondition{{
+			Type:   appsv1.DeploymentProgressing,
+			Status: "False",
+			Reason: "ProgressDeadlineExceeded",
+		}}
+		Expect(stateOf(d)).To(Equal(Failed))
+	})
+
+	It("honours the update strategy of StatefulSets", func() {
+		s := &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](3)},
+			Status: appsv1.StatefulSetStatus{
+				UpdatedReplicas: 1,
+				ReadyReplicas:   3,
+			},
+		}
+		Expect(stateOf(s)).To(Equal(Progressing))
+
+		s.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To[int32](2)}
+		Expect(stateOf(s)).To(Equal(Complete))
+
+		s.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+		s.Status.UpdatedReplicas = 0
+		Expect(stateOf(s)).To(Equal(Complete))
+	})
+
+	It("waits for every DaemonSet pod", func() {
+		d := &appsv1.DaemonSet{Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 5,
+			UpdatedNumberScheduled: 5,
+			NumberAvailable:        4,
+		}}
+		Expect(stateOf(d)).To(Equal(Progressing))
+		d.Status.NumberAvailable = 5
+		Expect(stateOf(d)).To(Equal(Complete))
+	})
+
+	It("does not wait for CronJobs", func() {
+		Expect(stateOf(&batchv1.CronJob{})).To(Equal(Complete))
+	})
+
+	Context("for other kinds", func() {
+		workload := func(status map[string]interface{}) *unstructured.Unstructured {
+			u := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "argoproj.io/v1alpha1",
+				"kind":       "Rollout",
+				"metadata":   map[string]interface{}{"name": "api", "generation": int64(4)},
+				"spec":       map[string]interface{}{"replicas": int64(2)},
+				"status":     status,
+			}}
+			return u
+		}
+
+		It("waits for the controller to observe the change", func() {
+			Expect(stateOf(workload(map[string]interface{}{"observedGeneration": int64(3)}))).To(Equal(Progressing))
+		})
+
+		It("reads the phase of Argo Rollouts", func() {
+			Expect(stateOf(workload(map[string]interface{}{"phase": "Degraded"}))).To(Equal(Failed))
+			Expect(stateOf(workload(map[string]interface{}{"phase": "Paused"}))).To(Equal(Progressing))
+		})
+
+		It("reads Ready and Progressing conditions", func() {
+			Expect(stateOf(workload(map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "Unknown"}},
+			}))).To(Equal(Progressing))
+			Expect(stateOf(workload(map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{
+					"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded",
+				}},
+			}))).To(Equal(Failed))
+		})
+
+		It("compares replica counts", func() {
+			Expect(stateOf(workload(map[string]interface{}{
+				"updatedReplicas": int64(2), "availableReplicas": int64(1),
+			}))).To(Equal(Progressing))
+			Expect(stateOf(workload(map[string]interface{}{
+				"phase": "Healthy", "updatedReplicas": int64(2), "availableReplicas": int64(2),
+			}))).To(Equal(Complete))
+		})
+	})
+})
@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretdata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Encode converts a value read from Vault into the bytes of a secret value.
+// Strings are written as is, numbers keep every digit Vault returned, and
+// nested maps and lists are serialized as JSON. Binary values, such as those
+// produced by DecodeBase64, are written unchanged.
+func Encode(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return []byte{}, nil
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case json.Number:
+		return []byte(v.String()), nil
+	case bool:
+		return []byte(strconv.FormatBool(v)), nil
+	case float64:
+		// Never use exponent notation, which %v does for large numbers
+		return []byte(strconv.FormatFloat(v, 'f', -1, 64)), nil
+	case float32:
+		return []byte(strconv.FormatFloat(float64(v), 'f', -1, 32)), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return []byte(fmt.Sprintf("%d", v)), nil
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode a %T as JSON: %w", v, err)
+	}
+	return out, nil
+}
+
+// EncodeAll encodes every value of data with Encode
+func EncodeAll(data map[string]interface{}) (map[string][]byte, error) {
+	encoded := make(map[string][]byte, len(data))
+	for k, v := range data {
+		value, err := Encode(v)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", k, err)
+		}
+		encoded[k] = value
+	}
+	return encoded, nil
+}
+
+// DecodeBase64 returns a copy of data in which the values of the keys
+// matching one of the glob patterns are base64 decoded to []byte. Line breaks
+// and padding are optional, so the output of base64(1) can be stored as is.
+func DecodeBase64(data map[string]interface{}, patterns []string) (map[string]interface{}, error) {
+	if len(patterns) == 0 {
+		return data, nil
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+		}
+	}
+
+	decoded := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		decoded[k] = v
+		if !MatchKey(patterns, k) {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("key %s: cannot base64 decode a %T", k, v)
+		}
+		value, err := decodeBase64(s)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", k, err)
+		}
+		decoded[k] = value
+	}
+	return decoded, nil
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.Join(strings.Fields(s), "")
+	value, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	return value, nil
+}
+
+// MatchKey reports whether key matches one of the glob patterns
+func MatchKey(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
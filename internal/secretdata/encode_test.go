@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretdata
+
+import (
+	"encoding/json"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Encode", func() {
+	It("writes strings as is", func() {
+		Expect(Encode("s3cr:t")).To(Equal([]byte("s3cr:t")))
+		Expect(Encode(nil)).To(BeEmpty())
+	})
+
+	It("serializes nested maps and lists as JSON", func() {
+		Expect(Encode(map[string]interface{}{"b": "2", "a": json.Number("1")})).
+			To(MatchJSON(`{"a": 1, "b": "2"}`))
+		Expect(Encode([]interface{}{"x", map[string]interface{}{"y": true}})).
+			To(MatchJSON(`["x", {"y": true}]`))
+	})
+
+	It("renders numbers without losing digits", func() {
+		Expect(Encode(json.Number("12345678901234567890"))).To(Equal([]byte("12345678901234567890")))
+		Expect(Encode(json.Number("0.1"))).To(Equal([]byte("0.1")))
+		Expect(Encode(float64(1e21))).To(Equal([]byte("1000000000000000000000")))
+		Expect(Encode(float64(3.25))).To(Equal([]byte("3.25")))
+		Expect(Encode(42)).To(Equal([]byte("42")))
+		Expect(Encode(true)).To(Equal([]byte("true")))
+	})
+
+	It("keeps large numbers from a Vault response intact", func() {
+		secret, err := vault.ParseSecret(strings.NewReader(
+			`{"data": {"account": 12345678901234567890, "limits": {"max": 1e3}}}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		encoded, err := EncodeAll(secret.Data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encoded).To(HaveKeyWithValue("account", []byte("12345678901234567890")))
+		Expect(encoded["limits"]).To(MatchJSON(`{"max": 1e3}`))
+	})
+})
+
+var _ = Describe("DecodeBase64", func() {
+	keystore := []byte{0xfe, 0xed, 0xfe, 0xed, 0x00, 0x00, 0x00, 0x02}
+
+	It("decodes the values of matching keys to bytes", func() {
+		data := map[string]interface{}{
+			"keystore.jks":  "/u3+7QAAAAI=",
+			"krb5.keytab":   "/u3+7QAAAAI",
+			"keystore-pass": "changeit",
+		}
+		decoded, err := DecodeBase64(data, []string{"*.jks", "*.keytab"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(HaveKeyWithValue("keystore.jks", keystore))
+		Expect(decoded).To(HaveKeyWithValue("krb5.keytab", keystore))
+		Expect(decoded).To(HaveKeyWithValue("keystore-pass", "changeit"))
+		// The Vault data itself is left alone
+		Expect(data).To(HaveKeyWithValue("keystore.jks", "/u3+7QAAAAI="))
+
+		encoded, err := EncodeAll(decoded)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encoded).To(HaveKeyWithValue("keystore.jks", keystore))
+	})
+
+	It("ignores line breaks in wrapped base64", func() {
+		decoded, err := DecodeBase64(map[string]interface{}{"blob": "/u3+\n7QAA\r\nAAI=\n"}, []string{"blob"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(HaveKeyWithValue("blob", keystore))
+	})
+
+	It("rejects values that are not base64 strings", func() {
+		_, err := DecodeBase64(map[string]interface{}{"blob": "not base64!"}, []string{"blob"})
+		Expect(err).To(MatchError(ContainSubstring("key blob: invalid base64")))
+
+		_, err = DecodeBase64(map[string]interface{}{"blob": json.Number("1")}, []string{"blob"})
+		Expect(err).To(MatchError(ContainSubstring("cannot base64 decode")))
+	})
+
+	It("rejects invalid patterns", func() {
+		_, err := DecodeBase64(map[string]interface{}{}, []string{"["})
+		Expect(err).To(MatchError(ContainSubstring("invalid key pattern")))
+	})
+})
@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretdata
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// FuncMap returns the helpers available in templates. They follow the names
+// and argument order of the sprig functions of the same name, so templates
+// written for other tools keep working. Helpers that are not deterministic,
+// such as now or randAlphaNum, are left out on purpose: they would change the
+// rendered secret, and restart workloads, on every sync.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		// Defaults and checks
+		"default":  defaultValue,
+		"empty":    empty,
+		"coalesce": coalesce,
+		"required": required,
+
+		// Strings
+		"toString":   toString,
+		"quote":      func(v interface{}) string { return fmt.Sprintf("%q", toString(v)) },
+		"squote":     func(v interface{}) string { return "'" + toString(v) + "'" },
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, repl, s string) string { return strings.ReplaceAll(s, old, repl) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":     func(count int, s string) string { return strings.Repeat(s, count) },
+		"indent":     indent,
+		"nindent":    func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+		"join":       join,
+		"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+
+		// Encodings
+		"b64enc":       func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"b64dec":       b64dec,
+		"sha256sum":    sha256sum,
+		"toJson":       toJSON,
+		"toPrettyJson": toPrettyJSON,
+		"fromJson":     fromJSON,
+	}
+}
+
+// empty reports whether v is nil or the zero value of its type
+func empty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	}
+	return rv.IsZero()
+}
+
+func defaultValue(def interface{}, given ...interface{}) interface{} {
+	if len(given) == 0 || empty(given[0]) {
+		return def
+	}
+	return given[0]
+}
+
+func coalesce(values ...interface{}) interface{} {
+	for _, v := range values {
+		if !empty(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+func required(msg string, v interface{}) (interface{}, error) {
+	if empty(v) {
+		return nil, errors.New(msg)
+	}
+	return v, nil
+}
+
+// toString formats v the way it would be written to the secret
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	if out, err := Encode(v); err == nil {
+		return string(out)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// join concatenates the elements of a list, which Vault returns as []interface{}
+func join(sep string, list interface{}) (string, error) {
+	switch list := list.(type) {
+	case []string:
+		return strings.Join(list, sep), nil
+	case []interface{}:
+		parts := make([]string, 0, len(list))
+		for _, v := range list {
+			parts = append(parts, toString(v))
+		}
+		return strings.Join(parts, sep), nil
+	case nil:
+		return "", nil
+	}
+	return "", fmt.Errorf("join: cannot join a %T", list)
+}
+
+func b64dec(s string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", fmt.Errorf("b64dec: %w", err)
+	}
+	return string(decoded), nil
+}
+
+func sha256sum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func toJSON(v interface{}) (string, error) {
+	out, err := json.Marshal(v)
+	return string(out), err
+}
+
+func toPrettyJSON(v interface{}) (string, error) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	return string(out), err
+}
+
+func fromJSON(s string) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("fromJson: %w", err)
+	}
+	return v, nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secretdata turns the data read from Vault into the contents of
+// the target Kubernetes Secret.
+package secretdata
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Metadata describes the Vault secret that templates are rendered from
+type Metadata struct {
+	// Mount is the mount path of the secrets engine
+	Mount string
+	// Path is the path of the secret within the mount
+	Path string
+	// Version is the KV v2 version that was read; zero for other engines
+	Version int
+	// CreatedTime is when the KV v2 version was written
+	CreatedTime time.Time
+	// LeaseID is the lease of dynamic credentials
+	LeaseID string
+	// LeaseDuration is how long the lease of dynamic credentials is valid
+	LeaseDuration time.Duration
+}
+
+// Source is the data read from one Vault source and its metadata
+type Source struct {
+	Data     map[string]interface{}
+	Metadata Metadata
+}
+
+// Input is what templates are executed against
+type Input struct {
+	// Data is the Vault data, or the merged data when there are several sources
+	Data map[string]interface{}
+	// Metadata describes the Vault secret; it is empty when there are several sources
+	Metadata Metadata
+	// Sources holds the data of each source by name when the secret is merged
+	// from several sources
+	Sources map[string]Source
+}
+
+// Parse checks that every template in templates parses and is keyed by a
+// valid secret key, without reading any data to render it with
+func Parse(templates map[string]string) error {
+	for _, key := range sortedKeys(templates) {
+		if _, err := parse(key, templates[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render executes each template in templates against input and returns the
+// output keyed like templates. Referencing a key that is missing from the
+// data is an error rather than rendering "<no value>".
+func Render(templates map[string]string, input Input) (map[string]string, error) {
+	rendered := make(map[string]string, len(templates))
+	for _, key := range sortedKeys(templates) {
+		tmpl, err := parse(key, templates[key])
+		if err != nil {
+			return nil, err
+		}
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, input); err != nil {
+			return nil, fmt.Errorf("failed to render template for key %s: %w", key, err)
+		}
+		rendered[key] = out.String()
+	}
+	return rendered, nil
+}
+
+// parse parses the template text for the secret key
+func parse(key, text string) (*template.Template, error) {
+	if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid secret key %q: %s", key, strings.Join(errs, ", "))
+	}
+	tmpl, err := template.New(key).Option("missingkey=error").Funcs(FuncMap()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template for key %s: %w", key, err)
+	}
+	return tmpl, nil
+}
+
+// sortedKeys returns the keys of templates in order, so the first failing
+// template is always the same
+func sortedKeys(templates map[string]string) []string {
+	keys := make([]string, 0, len(templates))
+	for k := range templates {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
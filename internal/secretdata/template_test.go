@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretdata
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Render", func() {
+	data := map[string]interface{}{
+		"username": "app",
+		"password": "s3cr:t",
+		"host":     "db.internal",
+		"port":     "5432",
+		"hosts":    []interface{}{"a", "b"},
+	}
+	metadata := Metadata{Mount: "secret", Path: "myapp/db", Version: 3, CreatedTime: time.Unix(0, 0).UTC()}
+
+	It("renders templates against the Vault data and metadata", func() {
+		rendered, err := Render(map[string]string{
+			"jdbc-url": `jdbc:postgresql://{{ .Data.host }}:{{ .Data.port }}/app?user={{ .Data.username }}`,
+			".pgpass":  `{{ .Data.host }}:{{ .Data.port }}:*:{{ .Data.username }}:{{ .Data.password }}`,
+			"version":  `{{ .Metadata.Mount }}/{{ .Metadata.Path }}@{{ .Metadata.Version }}`,
+		}, Input{Data: data, Metadata: metadata})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered).To(Equal(map[string]string{
+			"jdbc-url": "jdbc:postgresql://db.internal:5432/app?user=app",
+			".pgpass":  "db.internal:5432:*:app:s3cr:t",
+			"version":  "secret/myapp/db@3",
+		}))
+	})
+
+	It("provides sprig-like helpers", func() {
+		rendered, err := Render(map[string]string{
+			"env":     `DB_USER={{ .Data.username | upper | quote }}`,
+			"default": `{{ .Data.password | default "none" }}-{{ "" | default "none" }}`,
+			"hosts":   `{{ join "," .Data.hosts }}`,
+			"yaml":    "db:{{ printf \"user: %s\\npass: %s\" .Data.username .Data.password | nindent 2 }}",
+			"b64":     `{{ .Data.username | b64enc | b64dec }}`,
+			"json":    `{{ toJson .Data.hosts }}`,
+		}, Input{Data: data, Metadata: metadata})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered).To(HaveKeyWithValue("env", `DB_USER="APP"`))
+		Expect(rendered).To(HaveKeyWithValue("default", "s3cr:t-none"))
+		Expect(rendered).To(HaveKeyWithValue("hosts", "a,b"))
+		Expect(rendered).To(HaveKeyWithValue("yaml", "db:\n  user: app\n  pass: s3cr:t"))
+		Expect(rendered).To(HaveKeyWithValue("b64", "app"))
+		Expect(rendered).To(HaveKeyWithValue("json", `["a","b"]`))
+	})
+
+	It("exposes each source when the secret is merged from several", func() {
+		rendered, err := Render(map[string]string{
+			"summary": `{{ .Sources.db.Data.username }}@{{ (index .Sources "api-key").Metadata.Path }}:{{ .Data.token }}`,
+		}, Input{
+			Data: map[string]interface{}{"token": "t"},
+			Sources: map[string]Source{
+				"db":      {Data: data},
+				"api-key": {Data: map[string]interface{}{"token": "t"}, Metadata: Metadata{Path: "myapp/api"}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered).To(HaveKeyWithValue("summary", "app@myapp/api:t"))
+	})
+
+	It("fails on keys missing from the Vault data", func() {
+		_, err := Render(map[string]string{"url": `{{ .Data.hostname }}`}, Input{Data: data, Metadata: metadata})
+		Expect(err).To(MatchError(ContainSubstring("key url")))
+	})
+
+	It("fails on templates that do not parse", func() {
+		_, err := Render(map[string]string{"url": `{{ .Data.host `}, Input{Data: data, Metadata: metadata})
+		Expect(err).To(MatchError(ContainSubstring("failed to parse template for key url")))
+	})
+
+	It("rejects keys that are not valid secret keys", func() {
+		_, err := Render(map[string]string{"app config": `{{ .Data.host }}`}, Input{Data: data})
+		Expect(err).To(MatchError(ContainSubstring(`invalid secret key "app config"`)))
+	})
+
+	It("fails when a required value is empty", func() {
+		_, err := Render(map[string]string{"url": `{{ required "token is required" "" }}`}, Input{Data: data, Metadata: metadata})
+		Expect(err).To(MatchError(ContainSubstring("token is required")))
+	})
+})
+
+var _ = Describe("Parse", func() {
+	It("accepts templates without rendering them", func() {
+		Expect(Parse(map[string]string{"url": `{{ .Data.missing | upper }}`})).To(Succeed())
+	})
+
+	It("fails on templates that do not parse", func() {
+		Expect(Parse(map[string]string{"url": `{{ .Data.host `})).To(MatchError(ContainSubstring("failed to parse template for key url")))
+	})
+
+	It("rejects keys that are not valid secret keys", func() {
+		Expect(Parse(map[string]string{"app config": `x`})).To(MatchError(ContainSubstring(`invalid secret key "app config"`)))
+	})
+})
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	vault "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AppRoleRoleIDKey is the Secret key holding the AppRole role_id
+	AppRoleRoleIDKey = "role_id"
+	// AppRoleSecretIDKey is the Secret key holding the AppRole secret_id
+	AppRoleSecretIDKey = "secret_id"
+)
+
+// AppRoleAuth logs in using the Vault AppRole auth method. The role_id and
+// secret_id are read from files, or from a Kubernetes Secret when no files are
+// configured, on every login so that a rotated secret_id is picked up without
+// restarting the manager.
+type AppRoleAuth struct {
+	// MountPath is the path the auth method is mounted at (defaults to "approle")
+	MountPath string
+	// RoleIDFile and SecretIDFile hold the credentials, e.g. from a mounted Secret
+	RoleIDFile   string
+	SecretIDFile string
+	// SecretRef names a Secret with role_id and secret_id keys, read through Reader
+	SecretRef types.NamespacedName
+	Reader    client.Reader
+	// WrappedSecretID means the secret_id is delivered as a response-wrapping
+	// token that has to be unwrapped before it can be used
+	WrappedSecretID bool
+
+	mu sync.Mutex
+	// wrappingToken is the last wrapping token that was unwrapped into secretID.
+	// Wrapping tokens are single-use, so the unwrapped value is kept until a
+	// newly wrapped secret_id is delivered.
+	wrappingToken string
+	secretID      string
+}
+
+// Login exchanges the role_id and secret_id for a Vault token.
+func (a *AppRoleAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	roleID, secretID, err := a.credentials(ctx)
+	if err != nil {
+		return nil, &LoginError{Method: "approle", Err: err}
+	}
+
+	if a.WrappedSecretID {
+		secretID, err = a.unwrap(ctx, client, secretID)
+		if err != nil {
+			return nil, &LoginError{Method: "approle", Err: err}
+		}
+	}
+
+	mountPath := a.MountPath
+	if mountPath == "" {
+		mountPath = "approle"
+	}
+
+	return login(ctx, client, "approle", mountPath, map[string]interface{}{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	})
+}
+
+// credentials reads the role_id and secret_id from the configured source
+func (a *AppRoleAuth) credentials(ctx context.Context) (string, string, error) {
+	if a.RoleIDFile != "" || a.SecretIDFile != "" {
+		roleID, err := os.ReadFile(a.RoleIDFile)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read role_id: %w", err)
+		}
+		secretID, err := os.ReadFile(a.SecretIDFile)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read secret_id: %w", err)
+		}
+		return strings.TrimSpace(string(roleID)), strings.TrimSpace(string(secretID)), nil
+	}
+
+	if a.SecretRef.Name == "" || a.Reader == nil {
+		return "", "", errors.New("no role_id/secret_id files or Secret configured")
+	}
+	secret := &corev1.Secret{}
+	if err := a.Reader.Get(ctx, a.SecretRef, secret); err != nil {
+		return "", "", fmt.Errorf("failed to get Secret %s: %w", a.SecretRef, err)
+	}
+	roleID, secretID := secret.Data[AppRoleRoleIDKey], secret.Data[AppRoleSecretIDKey]
+	if len(roleID) == 0 || len(secretID) == 0 {
+		return "", "", fmt.Errorf("secret %s must contain %q and %q", a.SecretRef, AppRoleRoleIDKey, AppRoleSecretIDKey)
+	}
+	return strings.TrimSpace(string(roleID)), strings.TrimSpace(string(secretID)), nil
+}
+
+// unwrap returns the secret_id wrapped by wrappingToken, reusing the value
+// from a previous unwrap as long as the same token is still configured.
+func (a *AppRoleAuth) unwrap(ctx context.Context, client *vault.Client, wrappingToken string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if wrappingToken == a.wrappingToken && a.secretID != "" {
+		return a.secretID, nil
+	}
+
+	unwrapClient, err := client.CloneWithHeaders()
+	if err != nil {
+		return "", err
+	}
+	unwrapClient.ClearToken()
+	unwrapped, err := unwrapClient.Logical().UnwrapWithContext(ctx, wrappingToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap secret_id: %w", err)
+	}
+	if unwrapped == nil || unwrapped.Data == nil {
+		return "", errors.New("wrapped secret_id response is empty")
+	}
+	secretID, ok := unwrapped.Data["secret_id"].(string)
+	if !ok || secretID == "" {
+		return "", errors.New("wrapped response does not contain a secret_id")
+	}
+
+	a.wrappingToken = wrappingToken
+	a.secretID = secretID
+	return secretID, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("AppRole authentication", func() {
+	var fakeVault *fakeVault
+
+	BeforeEach(func() {
+		fakeVault = newFakeVault()
+		DeferCleanup(fakeVault.server.Close)
+	})
+
+	It("logs in with role_id and secret_id files", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "role_id"), []byte("the-role-id\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "secret_id"), []byte("the-secret-id\n"), 0o600)).To(Succeed())
+
+		client := fakeVault.client()
+		auth := &AppRoleAuth{
+			RoleIDFile:   filepath.Join(dir, "role_id"),
+			SecretIDFile: filepath.Join(dir, "secret_id"),
+		}
+		_, err := auth.Login(context.Background(), client)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Token()).To(Equal("s.approle"))
+		Expect(fakeVault.loginBody).To(HaveKeyWithValue("role_id", "the-role-id"))
+	})
+
+	It("reads credentials from a Secret and unwraps the secret_id once", func() {
+		reader := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "approle", Namespace: "system"},
+			Data: map[string][]byte{
+				AppRoleRoleIDKey:   []byte("the-role-id"),
+				AppRoleSecretIDKey: []byte("s.wrapping"),
+			},
+		}).Build()
+
+		auth := &AppRoleAuth{
+			SecretRef:       types.NamespacedName{Namespace: "system", Name: "approle"},
+			Reader:          reader,
+			WrappedSecretID: true,
+		}
+		for range 2 {
+			_, err := auth.Login(context.Background(), fakeVault.client())
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(fakeVault.logins.Load()).To(BeEquivalentTo(2))
+		Expect(fakeVault.unwraps.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("returns a LoginError when Vault rejects the credentials", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "role_id"), []byte("the-role-id"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "secret_id"), []byte("stale"), 0o600)).To(Succeed())
+
+		auth := &AppRoleAuth{
+			RoleIDFile:   filepath.Join(dir, "role_id"),
+			SecretIDFile: filepath.Join(dir, "secret_id"),
+		}
+		_, err := auth.Login(context.Background(), fakeVault.client())
+		var loginErr *LoginError
+		Expect(errors.As(err, &loginErr)).To(BeTrue())
+		Expect(loginErr.Method).To(Equal("approle"))
+	})
+})
@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vaultclient authenticates the operator against HashiCorp Vault and
+// keeps the resulting token alive for the lifetime of the manager.
+package vaultclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// DefaultServiceAccountTokenPath is where the kubelet projects the service
+// account token of the manager pod.
+const DefaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// Authenticator logs a Vault client in. On success the client carries the new
+// token and the auth response is returned so the caller can track its lease.
+// Failures are reported as *LoginError.
+type Authenticator interface {
+	Login(ctx context.Context, client *vault.Client) (*vault.Secret, error)
+}
+
+// LoginError is returned when an Authenticator fails to obtain a token. It
+// lets callers tell authentication problems apart from failed reads.
+type LoginError struct {
+	// Method is the auth method that failed (e.g. "kubernetes", "approle")
+	Method string
+	Err    error
+}
+
+func (e *LoginError) Error() string {
+	return fmt.Sprintf("vault %s login failed: %v", e.Method, e.Err)
+}
+
+func (e *LoginError) Unwrap() error {
+	return e.Err
+}
+
+// KubernetesAuth logs in using the Vault Kubernetes auth method with the
+// service account token of the manager pod.
+type KubernetesAuth struct {
+	// Role is the Vault role bound to the manager's service account
+	Role string
+	// MountPath is the path the auth method is mounted at (defaults to "kubernetes")
+	MountPath string
+	// TokenPath is the projected service account token file (defaults to DefaultServiceAccountTokenPath)
+	TokenPath string
+	// JWT returns the service account token to send instead of reading TokenPath (optional)
+	JWT func(ctx context.Context) (string, error)
+}
+
+// Login exchanges the service account token for a Vault token.
+func (a *KubernetesAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	if a.Role == "" {
+		return nil, &LoginError{Method: "kubernetes", Err: errors.New("role is required")}
+	}
+
+	jwt, err := a.serviceAccountToken(ctx)
+	if err != nil {
+		return nil, &LoginError{Method: "kubernetes", Err: err}
+	}
+
+	mountPath := a.MountPath
+	if mountPath == "" {
+		mountPath = "kubernetes"
+	}
+
+	return login(ctx, client, "kubernetes", mountPath, map[string]interface{}{
+		"role": a.Role,
+		"jwt":  jwt,
+	})
+}
+
+func (a *KubernetesAuth) serviceAccountToken(ctx context.Context) (string, error) {
+	if a.JWT != nil {
+		return a.JWT(ctx)
+	}
+
+	tokenPath := a.TokenPath
+	if tokenPath == "" {
+		tokenPath = DefaultServiceAccountTokenPath
+	}
+	// The kubelet rotates projected tokens, so read the file on every login
+	jwt, err := os.ReadFile(tokenPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read service account token: %w", err)
+	}
+	return strings.TrimSpace(string(jwt)), nil
+}
+
+// login writes the credentials to auth/<mountPath>/login and sets the
+// returned token on client.
+func login(ctx context.Context, client *vault.Client, method, mountPath string, data map[string]interface{}) (*vault.Secret, error) {
+	// Log in through a token-less clone so an expired token is never sent
+	loginClient, err := client.CloneWithHeaders()
+	if err != nil {
+		return nil, &LoginError{Method: method, Err: err}
+	}
+	loginClient.ClearToken()
+
+	path := fmt.Sprintf("auth/%s/login", strings.Trim(mountPath, "/"))
+	secret, err := loginClient.Logical().WriteWithContext(ctx, path, data)
+	if err != nil {
+		return nil, &LoginError{Method: method, Err: err}
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return nil, &LoginError{Method: method, Err: fmt.Errorf("%s returned no token", path)}
+	}
+
+	client.SetToken(secret.Auth.ClientToken)
+	return secret, nil
+}
+
+// TokenAuth uses a static token such as VAULT_TOKEN. Logging in only validates
+// the token and looks up its TTL so that it can be renewed; once the token
+// expires it cannot be replaced.
+type TokenAuth struct {
+	Token string
+}
+
+// Login sets the static token on client and returns its lookup as auth data.
+func (a *TokenAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	if a.Token == "" {
+		return nil, &LoginError{Method: "token", Err: errors.New("no token configured")}
+	}
+	client.SetToken(a.Token)
+
+	self, err := client.Auth().Token().LookupSelfWithContext(ctx)
+	if err != nil {
+		return nil, &LoginError{Method: "token", Err: fmt.Errorf("failed to look up token: %w", err)}
+	}
+	ttl, err := self.TokenTTL()
+	if err != nil {
+		return nil, &LoginError{Method: "token", Err: err}
+	}
+	renewable, err := self.TokenIsRenewable()
+	if err != nil {
+		return nil, &LoginError{Method: "token", Err: err}
+	}
+
+	return &vault.Secret{
+		Auth: &vault.SecretAuth{
+			ClientToken:   a.Token,
+			Renewable:     renewable,
+			LeaseDuration: int(ttl.Seconds()),
+		},
+	}, nil
+}
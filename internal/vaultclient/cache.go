@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// Connection describes how to reach a Vault server
+type Connection struct {
+	// Address of the Vault server; empty keeps the address of the base config
+	Address string
+	// CACert is a PEM encoded CA bundle used to verify the server certificate
+	CACert []byte
+	// TLSServerName is the SNI host name to use when connecting
+	TLSServerName string
+	// Namespace is the Vault Enterprise namespace
+	Namespace string
+}
+
+// NewClient builds a Vault client for conn on top of base, which supplies
+// defaults such as timeouts and the address when conn does not set one.
+func NewClient(base *vault.Config, conn Connection) (*vault.Client, error) {
+	config := vault.DefaultConfig()
+	if base != nil {
+		config.Address = base.Address
+		config.Timeout = base.Timeout
+		config.MaxRetries = base.MaxRetries
+	}
+	if conn.Address != "" {
+		config.Address = conn.Address
+	}
+	if len(conn.CACert) > 0 || conn.TLSServerName != "" {
+		if err := config.ConfigureTLS(&vault.TLSConfig{
+			CACertBytes:   conn.CACert,
+			TLSServerName: conn.TLSServerName,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := vault.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	// Never pick up VAULT_TOKEN from the environment for per-connection clients
+	client.ClearToken()
+	if conn.Namespace != "" {
+		client.SetNamespace(conn.Namespace)
+	}
+	return client, nil
+}
+
+// ClientCache holds one authenticated Vault client per key, typically a
+// VaultAuth object. An entry is rebuilt when the fingerprint of the objects it
+// was built from changes, and logged in again when its token nears expiry.
+// The token of a client that is replaced or deleted is revoked.
+type ClientCache struct {
+	// Base supplies defaults for every client built by the cache
+	Base *vault.Config
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	// mu serializes logins for the key, so that a slow Vault only holds up
+	// the SecretRotations using it
+	mu          sync.Mutex
+	fingerprint string
+	client      *vault.Client
+	// reauthAt is when the token should be replaced; zero if it never expires
+	reauthAt time.Time
+}
+
+// Get returns an authenticated client for key. fingerprint identifies the
+// configuration the client is built from (e.g. the resourceVersions of the
+// referenced objects); a cached client with a different fingerprint is
+// discarded. Login errors are returned as *LoginError.
+func (c *ClientCache) Get(ctx context.Context, key, fingerprint string, conn Connection, auth Authenticator) (*vault.Client, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &cacheEntry{}
+		if c.entries == nil {
+			c.entries = make(map[string]*cacheEntry)
+		}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.client != nil && entry.fingerprint == fingerprint &&
+		(entry.reauthAt.IsZero() || time.Now().Before(entry.reauthAt)) {
+		return entry.client, nil
+	}
+	if entry.client != nil {
+		revokeToken(ctx, entry.client)
+		entry.client = nil
+	}
+
+	client, err := NewClient(c.Base, conn)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := auth.Login(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.fingerprint = fingerprint
+	entry.client = client
+	entry.reauthAt = time.Time{}
+	if ttl := secret.Auth.LeaseDuration; ttl > 0 {
+		// Log in again after two thirds of the TTL, like the LifetimeWatcher does
+		entry.reauthAt = time.Now().Add(time.Duration(ttl) * time.Second * 2 / 3)
+	}
+	return client, nil
+}
+
+// Delete drops the client cached for key and revokes its token
+func (c *ClientCache) Delete(ctx context.Context, key string) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	delete(c.entries, key)
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.client != nil {
+		revokeToken(ctx, entry.client)
+		entry.client = nil
+	}
+}
+
+// revokeToken revokes the token of client. A token that cannot be revoked
+// still expires with its TTL, so errors are ignored.
+func revokeToken(ctx context.Context, client *vault.Client) {
+	if client.Token() == "" {
+		return
+	}
+	_ = client.Auth().Token().RevokeSelfWithContext(ctx, "")
+}
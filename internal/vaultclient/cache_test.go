@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// authFunc logs in by calling the function
+type authFunc func(ctx context.Context, client *vault.Client) (*vault.Secret, error)
+
+func (f authFunc) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	return f(ctx, client)
+}
+
+var _ = Describe("ClientCache", func() {
+	var cache *ClientCache
+	var logins atomic.Int32
+	login := authFunc(func(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+		logins.Add(1)
+		return &vault.Secret{Auth: &vault.SecretAuth{LeaseDuration: 3600}}, nil
+	})
+
+	BeforeEach(func() {
+		cache = &ClientCache{Base: vault.DefaultConfig()}
+		logins.Store(0)
+	})
+
+	It("reuses the client until the fingerprint changes", func() {
+		first, err := cache.Get(context.Background(), "team-a", "1", Connection{}, login)
+		Expect(err).NotTo(HaveOccurred())
+		again, err := cache.Get(context.Background(), "team-a", "1", Connection{}, login)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(BeIdenticalTo(first))
+		Expect(logins.Load()).To(Equal(int32(1)))
+
+		changed, err := cache.Get(context.Background(), "team-a", "2", Connection{}, login)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).NotTo(BeIdenticalTo(first))
+		Expect(logins.Load()).To(Equal(int32(2)))
+	})
+
+	It("does not hold up other keys while a login hangs", func() {
+		release := make(chan struct{})
+		defer close(release)
+		hanging := authFunc(func(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+			<-release
+			return &vault.Secret{Auth: &vault.SecretAuth{}}, nil
+		})
+		go func() {
+			defer GinkgoRecover()
+			_, _ = cache.Get(context.Background(), "unreachable", "1", Connection{}, hanging)
+		}()
+
+		done := make(chan error)
+		go func() {
+			_, err := cache.Get(context.Background(), "team-a", "1", Connection{}, login)
+			done <- err
+		}()
+		Eventually(done, time.Second).Should(Receive(BeNil()))
+	})
+
+	It("logs in again after a failed login", func() {
+		failing := authFunc(func(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+			return nil, &LoginError{Method: "test", Err: context.DeadlineExceeded}
+		})
+		_, err := cache.Get(context.Background(), "team-a", "1", Connection{}, failing)
+		Expect(err).To(HaveOccurred())
+		_, err = cache.Get(context.Background(), "team-a", "1", Connection{}, login)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(logins.Load()).To(Equal(int32(1)))
+	})
+
+	It("revokes the token of a replaced or deleted client", func() {
+		var revoked []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v1/auth/token/revoke-self", func(w http.ResponseWriter, r *http.Request) {
+			revoked = append(revoked, r.Header.Get("X-Vault-Token"))
+			w.WriteHeader(http.StatusNoContent)
+		})
+		server := httptest.NewServer(mux)
+		DeferCleanup(server.Close)
+
+		var issued atomic.Int32
+		tokenLogin := authFunc(func(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+			client.SetToken(fmt.Sprintf("token-%d", issued.Add(1)))
+			return &vault.Secret{Auth: &vault.SecretAuth{LeaseDuration: 3600}}, nil
+		})
+		conn := Connection{Address: server.URL}
+
+		_, err := cache.Get(context.Background(), "team-a", "1", conn, tokenLogin)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revoked).To(BeEmpty())
+
+		_, err = cache.Get(context.Background(), "team-a", "2", conn, tokenLogin)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revoked).To(Equal([]string{"token-1"}))
+
+		cache.Delete(context.Background(), "team-a")
+		Expect(revoked).To(Equal([]string{"token-1", "token-2"}))
+
+		cache.Delete(context.Background(), "team-a")
+		Expect(revoked).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// DatabaseCredentialsPath returns the path credentials for role are read from
+func DatabaseCredentialsPath(mount, role string) string {
+	if mount == "" {
+		mount = "database"
+	}
+	return fmt.Sprintf("%s/creds/%s", strings.Trim(mount, "/"), role)
+}
+
+// ReadDatabaseCredentials requests new credentials for role from the
+// database secrets engine mounted at mount. Every call creates a new lease.
+func ReadDatabaseCredentials(ctx context.Context, client *vault.Client, mount, role string) (*vault.Secret, error) {
+	path := DatabaseCredentialsPath(mount, role)
+	secret, err := client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("%w: at %s", vault.ErrSecretNotFound, path)
+	}
+	if secret.LeaseID == "" {
+		return nil, fmt.Errorf("%s returned credentials without a lease", path)
+	}
+	return secret, nil
+}
+
+// RevokeLease revokes leaseID, invalidating the credentials issued with it
+func RevokeLease(ctx context.Context, client *vault.Client, leaseID string) error {
+	return client.Sys().RevokeWithContext(ctx, leaseID)
+}
+
+// RenewLease asks Vault to extend leaseID by increment seconds. Vault may
+// grant less than requested once the lease approaches its max TTL.
+func RenewLease(ctx context.Context, client *vault.Client, leaseID string, increment int) (*vault.Secret, error) {
+	secret, err := client.Sys().RenewWithContext(ctx, leaseID, increment)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("renewal of lease %s returned no data", leaseID)
+	}
+	return secret, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	vault "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dynamic credentials", func() {
+	var client *vault.Client
+	var revoked []string
+
+	BeforeEach(func() {
+		revoked = nil
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v1/database/creds/app", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]interface{}{
+				"lease_id":       "database/creds/app/abc",
+				"lease_duration": 3600,
+				"renewable":      true,
+				"data":           map[string]interface{}{"username": "v-app-1", "password": "pw"},
+			})
+		})
+		mux.HandleFunc("/v1/static/creds/app", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"username": "app"}})
+		})
+		mux.HandleFunc("/v1/sys/leases/renew", func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				LeaseID   string `json:"lease_id"`
+				Increment int    `json:"increment"`
+			}
+			Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+			Expect(body.LeaseID).To(Equal("database/creds/app/abc"))
+			// Pretend the lease is capped by its max TTL
+			writeJSON(w, map[string]interface{}{
+				"lease_id":       body.LeaseID,
+				"lease_duration": body.Increment / 2,
+				"renewable":      true,
+			})
+		})
+		mux.HandleFunc("/v1/sys/leases/revoke", func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				LeaseID string `json:"lease_id"`
+			}
+			Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+			revoked = append(revoked, body.LeaseID)
+			w.WriteHeader(http.StatusNoContent)
+		})
+		server := httptest.NewServer(mux)
+		DeferCleanup(server.Close)
+
+		cfg := vault.DefaultConfig()
+		cfg.Address = server.URL
+		var err error
+		client, err = vault.NewClient(cfg)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("defaults the mount to database", func() {
+		Expect(DatabaseCredentialsPath("", "app")).To(Equal("database/creds/app"))
+		Expect(DatabaseCredentialsPath("/db/", "app")).To(Equal("db/creds/app"))
+	})
+
+	It("reads credentials together with their lease", func() {
+		secret, err := ReadDatabaseCredentials(context.Background(), client, "", "app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.LeaseID).To(Equal("database/creds/app/abc"))
+		Expect(secret.LeaseDuration).To(Equal(3600))
+		Expect(secret.Data).To(HaveKeyWithValue("username", "v-app-1"))
+	})
+
+	It("rejects credentials without a lease", func() {
+		_, err := ReadDatabaseCredentials(context.Background(), client, "static", "app")
+		Expect(err).To(MatchError(ContainSubstring("without a lease")))
+	})
+
+	It("reports the duration Vault granted on renewal", func() {
+		secret, err := RenewLease(context.Background(), client, "database/creds/app/abc", 3600)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.LeaseDuration).To(Equal(1800))
+	})
+
+	It("revokes leases", func() {
+		Expect(RevokeLease(context.Background(), client, "database/creds/app/abc")).To(Succeed())
+		Expect(revoked).To(Equal([]string{"database/creds/app/abc"}))
+	})
+})
@@ -0,0 +1,228 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/websocket"
+	vault "github.com/hashicorp/vault/api"
+)
+
+const (
+	// DefaultEventType subscribes to every KV event (kv-v1/write,
+	// kv-v2/data-write, kv-v2/delete, ...)
+	DefaultEventType = "kv*"
+
+	defaultEventMinBackoff = time.Second
+	defaultEventMaxBackoff = time.Minute
+)
+
+// Event is a single notification received from the Vault events API
+type Event struct {
+	// ID is the unique event id assigned by Vault
+	ID string
+	// EventType is the Vault event type, e.g. kv-v2/data-write
+	EventType string
+	// Operation is the plugin operation that triggered the event
+	Operation string
+	// MountPath is the mount the event originated from, without slashes
+	MountPath string
+	// Path is the API path that was written, including the mount
+	Path string
+	// DataPath is the path the secret data can be read from, including the
+	// mount. For kv-v2 metadata events it points at the data endpoint.
+	DataPath string
+}
+
+// EventSubscriber streams events from the Vault events WebSocket API
+// (Vault 1.13+) and hands each one to OnEvent. A dropped connection is
+// re-established with exponential backoff between MinBackoff and MaxBackoff.
+type EventSubscriber struct {
+	Client *vault.Client
+	// EventType is the event type pattern to subscribe to; defaults to
+	// DefaultEventType
+	EventType string
+	OnEvent   func(ctx context.Context, event Event)
+	Log       logr.Logger
+
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Start implements manager.Runnable. It blocks until ctx is cancelled.
+// Events are only needed by the elected leader, so the subscriber relies on
+// the default leader election behaviour.
+func (s *EventSubscriber) Start(ctx context.Context) error {
+	minBackoff, maxBackoff := s.MinBackoff, s.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = defaultEventMinBackoff
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = max(defaultEventMaxBackoff, minBackoff)
+	}
+
+	backoff := minBackoff
+	for {
+		connected, err := s.subscribe(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		// Only back off further while we keep failing to connect
+		if connected {
+			backoff = minBackoff
+		}
+		s.Log.Error(err, "Vault event subscription dropped, reconnecting", "retryAfter", backoff)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// subscribe reads events from a single WebSocket connection until it fails.
+// connected reports whether the handshake succeeded.
+func (s *EventSubscriber) subscribe(ctx context.Context) (connected bool, err error) {
+	endpoint, err := s.endpoint()
+	if err != nil {
+		return false, err
+	}
+
+	header := http.Header{}
+	header.Set("X-Vault-Token", s.Client.Token())
+	if ns := s.Client.Namespace(); ns != "" {
+		header.Set("X-Vault-Namespace", ns)
+	}
+
+	dialer := *websocket.DefaultDialer
+	if transport, ok := s.Client.CloneConfig().HttpClient.Transport.(*http.Transport); ok {
+		dialer.TLSClientConfig = transport.TLSClientConfig
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, endpoint, header)
+	if err != nil {
+		if resp != nil {
+			return false, fmt.Errorf("failed to subscribe to Vault events: %w (HTTP %d)", err, resp.StatusCode)
+		}
+		return false, fmt.Errorf("failed to subscribe to Vault events: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Unblock ReadMessage once the manager shuts down
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	s.Log.Info("Subscribed to Vault events", "eventType", s.eventType())
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return true, err
+		}
+		event, err := ParseEvent(message)
+		if err != nil {
+			s.Log.Error(err, "ignoring malformed Vault event")
+			continue
+		}
+		s.Log.V(1).Info("Received Vault event", "eventType", event.EventType, "path", event.DataPath)
+		s.OnEvent(ctx, event)
+	}
+}
+
+func (s *EventSubscriber) eventType() string {
+	if s.EventType == "" {
+		return DefaultEventType
+	}
+	return s.EventType
+}
+
+// endpoint returns the WebSocket URL of the subscription for the client's
+// Vault address
+func (s *EventSubscriber) endpoint() (string, error) {
+	u, err := url.Parse(s.Client.Address())
+	if err != nil {
+		return "", fmt.Errorf("invalid Vault address: %w", err)
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	default:
+		return "", fmt.Errorf("unsupported Vault address scheme %q for events", u.Scheme)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/v1/sys/events/subscribe/" + s.eventType()
+	u.RawQuery = url.Values{"json": []string{"true"}}.Encode()
+	return u.String(), nil
+}
+
+// cloudEvent is the subset of the CloudEvents envelope Vault sends with
+// json=true that the operator needs
+type cloudEvent struct {
+	ID   string `json:"id"`
+	Data struct {
+		EventType string `json:"event_type"`
+		Event     struct {
+			ID       string `json:"id"`
+			Metadata struct {
+				Path      string `json:"path"`
+				DataPath  string `json:"data_path"`
+				Operation string `json:"operation"`
+			} `json:"metadata"`
+		} `json:"event"`
+		PluginInfo struct {
+			MountPath string `json:"mount_path"`
+		} `json:"plugin_info"`
+	} `json:"data"`
+}
+
+// ParseEvent decodes a JSON encoded Vault event
+func ParseEvent(message []byte) (Event, error) {
+	var ce cloudEvent
+	if err := json.Unmarshal(message, &ce); err != nil {
+		return Event{}, err
+	}
+
+	metadata := ce.Data.Event.Metadata
+	event := Event{
+		ID:        ce.Data.Event.ID,
+		EventType: ce.Data.EventType,
+		Operation: metadata.Operation,
+		MountPath: strings.Trim(ce.Data.PluginInfo.MountPath, "/"),
+		Path:      strings.Trim(metadata.Path, "/"),
+		DataPath:  strings.Trim(metadata.DataPath, "/"),
+	}
+	if event.ID == "" {
+		event.ID = ce.ID
+	}
+	if event.DataPath == "" {
+		event.DataPath = event.Path
+	}
+	if event.DataPath == "" {
+		return Event{}, fmt.Errorf("event %s of type %q carries no path", event.ID, event.EventType)
+	}
+	return event, nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/websocket"
+	vault "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func kvEvent(id, path string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":   "envelope-" + id,
+		"type": "*",
+		"data": map[string]interface{}{
+			"event_type": "kv-v2/data-write",
+			"event": map[string]interface{}{
+				"id": id,
+				"metadata": map[string]interface{}{
+					"path":      path,
+					"data_path": path,
+					"operation": "data-write",
+				},
+			},
+			"plugin_info": map[string]interface{}{"mount_path": "secret/", "plugin": "kv"},
+		},
+	}
+}
+
+var _ = Describe("EventSubscriber", func() {
+	var (
+		client      *vault.Client
+		connections atomic.Int32
+		token       atomic.Value
+	)
+
+	BeforeEach(func() {
+		connections.Store(0)
+		upgrader := websocket.Upgrader{}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v1/sys/events/subscribe/kv*", func(w http.ResponseWriter, r *http.Request) {
+			token.Store(r.Header.Get("X-Vault-Token"))
+			if r.URL.Query().Get("json") != "true" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+
+			// Drop the first connection right after its event so the
+			// subscriber has to reconnect to see the second one
+			n := connections.Add(1)
+			if n == 1 {
+				_ = conn.WriteJSON(kvEvent("1", "secret/data/app/db"))
+				return
+			}
+			_ = conn.WriteJSON(kvEvent("2", "secret/data/app/api"))
+			// Hold the connection open until the client goes away
+			_, _, _ = conn.ReadMessage()
+		})
+		server := httptest.NewServer(mux)
+		DeferCleanup(server.Close)
+
+		cfg := vault.DefaultConfig()
+		cfg.Address = server.URL
+		var err error
+		client, err = vault.NewClient(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		client.SetToken("s.events")
+	})
+
+	It("reconnects after the subscription drops", func() {
+		events := make(chan Event, 10)
+		subscriber := &EventSubscriber{
+			Client:     client,
+			Log:        logr.Discard(),
+			MinBackoff: 10 * time.Millisecond,
+			MaxBackoff: 50 * time.Millisecond,
+			OnEvent: func(_ context.Context, event Event) {
+				events <- event
+			},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- subscriber.Start(ctx) }()
+
+		var event Event
+		Eventually(events).Should(Receive(&event))
+		Expect(event.ID).To(Equal("1"))
+		Expect(event.EventType).To(Equal("kv-v2/data-write"))
+		Expect(event.MountPath).To(Equal("secret"))
+		Expect(event.DataPath).To(Equal("secret/data/app/db"))
+
+		Eventually(events).Should(Receive(&event))
+		Expect(event.DataPath).To(Equal("secret/data/app/api"))
+		Expect(connections.Load()).To(BeEquivalentTo(2))
+		Expect(token.Load()).To(Equal("s.events"))
+
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("falls back to the event path when no data path is set", func() {
+		event, err := ParseEvent([]byte(`{"id":"x","data":{"event_type":"kv-v1/write",` +
+			`"event":{"metadata":{"path":"/kv/app/"}},"plugin_info":{"mount_path":"kv/"}}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(event.ID).To(Equal("x"))
+		Expect(event.DataPath).To(Equal("kv/app"))
+	})
+
+	It("rejects events without a path", func() {
+		_, err := ParseEvent([]byte(`{"id":"x","data":{"event_type":"kv-v2/data-write"}}`))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// ErrSecretDeleted is returned when the requested KV v2 version was
+// soft-deleted or destroyed, so that it is never mistaken for empty data.
+var ErrSecretDeleted = errors.New("secret version is deleted")
+
+// ReadKVv1 reads the secret at path from the KV v1 engine mounted at mount.
+func ReadKVv1(ctx context.Context, client *vault.Client, mount, path string) (*vault.KVSecret, error) {
+	return client.KVv1(mount).Get(ctx, path)
+}
+
+// ReadKVv2 reads the secret at path from the KV v2 engine mounted at mount.
+// version pins a specific version; zero reads the latest one. The returned
+// secret carries the version metadata that was read.
+func ReadKVv2(ctx context.Context, client *vault.Client, mount, path string, version int) (*vault.KVSecret, error) {
+	kv := client.KVv2(mount)
+
+	var secret *vault.KVSecret
+	var err error
+	if version > 0 {
+		secret, err = kv.GetVersion(ctx, path, version)
+	} else {
+		secret, err = kv.Get(ctx, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if secret.Data == nil && secret.VersionMetadata != nil {
+		metadata := secret.VersionMetadata
+		if metadata.Destroyed {
+			return secret, fmt.Errorf("%w: version %d of %s/%s was destroyed",
+				ErrSecretDeleted, metadata.Version, mount, path)
+		}
+		return secret, fmt.Errorf("%w: version %d of %s/%s was deleted at %s",
+			ErrSecretDeleted, metadata.Version, mount, path, metadata.DeletionTime)
+	}
+	return secret, nil
+}
+
+// WriteKVv2 writes data as a new version of the secret at path in the KV v2
+// engine mounted at mount. The write only succeeds while cas is still the
+// latest version, so concurrent writers are never overwritten.
+func WriteKVv2(ctx context.Context, client *vault.Client, mount, path string, data map[string]interface{}, cas int) (*vault.KVSecret, error) {
+	secret, err := client.KVv2(mount).Put(ctx, path, data, vault.WithCheckAndSet(cas))
+	if err != nil {
+		return nil, err
+	}
+	secret.Data = data
+	return secret, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	vault "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KV reads", func() {
+	var client *vault.Client
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v1/secret/data/app", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut || r.Method == http.MethodPost {
+				var body struct {
+					Options struct {
+						CAS int `json:"cas"`
+					} `json:"options"`
+				}
+				Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+				if body.Options.CAS != 3 {
+					w.WriteHeader(http.StatusBadRequest)
+					writeJSON(w, map[string]interface{}{"errors": []string{"check-and-set parameter did not match the current version"}})
+					return
+				}
+				writeJSON(w, map[string]interface{}{"data": map[string]interface{}{
+					"version": 4, "created_time": "2025-01-03T00:00:00Z", "deletion_time": "", "destroyed": false,
+				}})
+				return
+			}
+			switch r.URL.Query().Get("version") {
+			case "", "3":
+				writeJSON(w, map[string]interface{}{"data": map[string]interface{}{
+					"data": map[string]interface{}{"password": "s3cret"},
+					"metadata": map[string]interface{}{
+						"version": 3, "created_time": "2025-01-02T03:04:05Z", "deletion_time": "", "destroyed": false,
+					},
+				}})
+			case "2":
+				w.WriteHeader(http.StatusNotFound)
+				writeJSON(w, map[string]interface{}{"data": map[string]interface{}{
+					"data": nil,
+					"metadata": map[string]interface{}{
+						"version": 2, "created_time": "2025-01-01T03:04:05Z", "deletion_time": "2025-01-02T00:00:00Z", "destroyed": false,
+					},
+				}})
+			case "1":
+				w.WriteHeader(http.StatusNotFound)
+				writeJSON(w, map[string]interface{}{"data": map[string]interface{}{
+					"data": nil,
+					"metadata": map[string]interface{}{
+						"version": 1, "created_time": "2025-01-01T00:00:00Z", "deletion_time": "", "destroyed": true,
+					},
+				}})
+			}
+		})
+		mux.HandleFunc("/v1/kv/app", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"password": "v1"}})
+		})
+		server := httptest.NewServer(mux)
+		DeferCleanup(server.Close)
+
+		cfg := vault.DefaultConfig()
+		cfg.Address = server.URL
+		var err error
+		client, err = vault.NewClient(cfg)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reads the latest KV v2 version with its metadata", func() {
+		secret, err := ReadKVv2(context.Background(), client, "secret", "app", 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Data).To(HaveKeyWithValue("password", "s3cret"))
+		Expect(secret.VersionMetadata.Version).To(Equal(3))
+	})
+
+	It("detects soft-deleted and destroyed versions", func() {
+		_, err := ReadKVv2(context.Background(), client, "secret", "app", 2)
+		Expect(errors.Is(err, ErrSecretDeleted)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("deleted"))
+
+		_, err = ReadKVv2(context.Background(), client, "secret", "app", 1)
+		Expect(errors.Is(err, ErrSecretDeleted)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("destroyed"))
+	})
+
+	It("reads KV v1 secrets without unwrapping", func() {
+		secret, err := ReadKVv1(context.Background(), client, "kv", "app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Data).To(HaveKeyWithValue("password", "v1"))
+	})
+
+	It("writes a new KV v2 version with check-and-set", func() {
+		data := map[string]interface{}{"password": "rotated"}
+		secret, err := WriteKVv2(context.Background(), client, "secret", "app", data, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.VersionMetadata.Version).To(Equal(4))
+		Expect(secret.Data).To(Equal(data))
+
+		_, err = WriteKVv2(context.Background(), client, "secret", "app", data, 2)
+		Expect(err).To(MatchError(ContainSubstring("check-and-set")))
+	})
+})
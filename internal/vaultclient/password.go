@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+const (
+	// DefaultPasswordLength is the length of generated passwords
+	DefaultPasswordLength = 32
+	// DefaultPasswordCharset is the set of characters generated passwords are drawn from
+	DefaultPasswordCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// PasswordGenerator generates new secret values, either locally or with a
+// Vault password policy
+type PasswordGenerator struct {
+	// Length is the number of characters generated locally (defaults to DefaultPasswordLength)
+	Length int
+	// Charset is the set of characters generated locally (defaults to DefaultPasswordCharset)
+	Charset string
+	// Policy is the name of a Vault password policy; when set, Vault generates the value
+	Policy string
+}
+
+// Generate returns a new random value
+func (g PasswordGenerator) Generate(ctx context.Context, client *vault.Client) (string, error) {
+	if g.Policy != "" {
+		return generateWithPolicy(ctx, client, g.Policy)
+	}
+
+	length := g.Length
+	if length <= 0 {
+		length = DefaultPasswordLength
+	}
+	charset := []rune(g.Charset)
+	if len(charset) == 0 {
+		charset = []rune(DefaultPasswordCharset)
+	}
+
+	password := make([]rune, length)
+	limit := big.NewInt(int64(len(charset)))
+	for i := range password {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		password[i] = charset[n.Int64()]
+	}
+	return string(password), nil
+}
+
+// generateWithPolicy asks Vault to generate a password from the named policy
+func generateWithPolicy(ctx context.Context, client *vault.Client, policy string) (string, error) {
+	path := fmt.Sprintf("sys/policies/password/%s/generate", policy)
+	secret, err := client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil || secret.Data == nil {
+		return "", fmt.Errorf("%w: at %s", vault.ErrSecretNotFound, path)
+	}
+	password, _ := secret.Data["password"].(string)
+	if password == "" {
+		return "", errors.New(path + " returned no password")
+	}
+	return password, nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	vault "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PasswordGenerator", func() {
+	var client *vault.Client
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v1/sys/policies/password/strong/generate", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"password": "from-vault"}})
+		})
+		server := httptest.NewServer(mux)
+		DeferCleanup(server.Close)
+
+		cfg := vault.DefaultConfig()
+		cfg.Address = server.URL
+		var err error
+		client, err = vault.NewClient(cfg)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("generates passwords of the default length and charset", func() {
+		password, err := PasswordGenerator{}.Generate(context.Background(), client)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(password).To(HaveLen(DefaultPasswordLength))
+		Expect(password).To(MatchRegexp("^[a-zA-Z0-9]+$"))
+	})
+
+	It("honours length and charset", func() {
+		password, err := PasswordGenerator{Length: 12, Charset: "xyzé"}.Generate(context.Background(), client)
+		Expect(err).NotTo(HaveOccurred())
+		Expect([]rune(password)).To(HaveLen(12))
+		Expect(password).To(MatchRegexp("^[xyzé]+$"))
+	})
+
+	It("uses a Vault password policy when named", func() {
+		password, err := PasswordGenerator{Policy: "strong", Length: 8}.Generate(context.Background(), client)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(password).To(Equal("from-vault"))
+
+		_, err = PasswordGenerator{Policy: "missing"}.Generate(context.Background(), client)
+		Expect(err).To(HaveOccurred())
+	})
+})
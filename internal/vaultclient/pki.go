@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// CertificateRequest holds the parameters of a PKI issue request
+type CertificateRequest struct {
+	CommonName string
+	AltNames   []string
+	IPSANs     []string
+	// TTL is the requested lifetime; zero uses the role's TTL
+	TTL time.Duration
+}
+
+// Certificate is a certificate issued by the PKI engine
+type Certificate struct {
+	// Certificate, PrivateKey and IssuingCA are PEM encoded
+	Certificate string
+	PrivateKey  string
+	IssuingCA   string
+	// CAChain holds the PEM encoded issuing CA followed by the CAs above it
+	CAChain      []string
+	SerialNumber string
+	NotBefore    time.Time
+	NotAfter     time.Time
+	Raw          *vault.Secret
+}
+
+// PKIIssuePath returns the path certificates for role are issued from
+func PKIIssuePath(mount, role string) string {
+	if mount == "" {
+		mount = "pki"
+	}
+	return fmt.Sprintf("%s/issue/%s", strings.Trim(mount, "/"), role)
+}
+
+// IssueCertificate issues a new certificate and private key for role from
+// the PKI engine mounted at mount
+func IssueCertificate(ctx context.Context, client *vault.Client, mount, role string, req CertificateRequest) (*Certificate, error) {
+	path := PKIIssuePath(mount, role)
+	data := map[string]interface{}{"common_name": req.CommonName}
+	if len(req.AltNames) > 0 {
+		data["alt_names"] = strings.Join(req.AltNames, ",")
+	}
+	if len(req.IPSANs) > 0 {
+		data["ip_sans"] = strings.Join(req.IPSANs, ",")
+	}
+	if req.TTL > 0 {
+		data["ttl"] = req.TTL.String()
+	}
+
+	secret, err := client.Logical().WriteWithContext(ctx, path, data)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("%w: at %s", vault.ErrSecretNotFound, path)
+	}
+
+	cert := &Certificate{Raw: secret}
+	cert.Certificate, _ = secret.Data["certificate"].(string)
+	cert.PrivateKey, _ = secret.Data["private_key"].(string)
+	cert.IssuingCA, _ = secret.Data["issuing_ca"].(string)
+	cert.SerialNumber, _ = secret.Data["serial_number"].(string)
+	if chain, ok := secret.Data["ca_chain"].([]interface{}); ok {
+		for _, ca := range chain {
+			if ca, ok := ca.(string); ok && ca != "" {
+				cert.CAChain = append(cert.CAChain, ca)
+			}
+		}
+	}
+	if cert.Certificate == "" || cert.PrivateKey == "" {
+		return nil, fmt.Errorf("%s returned no certificate or private key", path)
+	}
+
+	parsed, err := ParseCertificate(cert.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("%s returned an invalid certificate: %w", path, err)
+	}
+	cert.NotBefore = parsed.NotBefore
+	cert.NotAfter = parsed.NotAfter
+	return cert, nil
+}
+
+// FullChain returns the certificate followed by the intermediate CAs that
+// issued it, the bundle a TLS server presents. Self-signed root CAs are left out.
+func (c *Certificate) FullChain() string {
+	bundle := strings.TrimSpace(c.Certificate) + "\n"
+	for _, ca := range c.chain() {
+		if !selfSigned(ca) {
+			bundle += strings.TrimSpace(ca) + "\n"
+		}
+	}
+	return bundle
+}
+
+// CA returns the last CA of the chain, which is the root CA when Vault knows
+// the whole chain, or "" when Vault returned no CA
+func (c *Certificate) CA() string {
+	chain := c.chain()
+	if len(chain) == 0 {
+		return ""
+	}
+	return chain[len(chain)-1]
+}
+
+// chain returns the CAs above the certificate, falling back to the issuing
+// CA for Vault versions that return no ca_chain
+func (c *Certificate) chain() []string {
+	if len(c.CAChain) > 0 {
+		return c.CAChain
+	}
+	if c.IssuingCA != "" {
+		return []string{c.IssuingCA}
+	}
+	return nil
+}
+
+// selfSigned reports whether the PEM encoded certificate is a self-signed root
+func selfSigned(data string) bool {
+	cert, err := ParseCertificate(data)
+	return err == nil && bytes.Equal(cert.RawSubject, cert.RawIssuer) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+// ParseCertificate parses the first certificate of a PEM bundle
+func ParseCertificate(data string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	vault "github.com/hashicorp/vault/api"
+)
+
+// loginRetryInterval is how long to wait before retrying a failed login
+const loginRetryInterval = 10 * time.Second
+
+// TokenManager keeps the token of a shared Vault client alive for the lifetime
+// of the manager. It logs in with Auth, renews the token with a Vault
+// LifetimeWatcher and logs in again once renewal fails or the token reaches
+// its max TTL. Token health is exposed through Checker for the readyz endpoint.
+type TokenManager struct {
+	Client *vault.Client
+	Auth   Authenticator
+	Log    logr.Logger
+
+	mu sync.RWMutex
+	// authenticated is true while the client holds a token obtained by Auth
+	authenticated bool
+	// expiresAt is when the current token expires; zero if it never does
+	expiresAt time.Time
+	// lastErr is the most recent login or renewal error
+	lastErr error
+}
+
+// Start implements manager.Runnable. It blocks until ctx is cancelled.
+func (m *TokenManager) Start(ctx context.Context) error {
+	for {
+		secret, err := m.Auth.Login(ctx, m.Client)
+		if err != nil {
+			m.setFailed(err)
+			m.Log.Error(err, "failed to log in to Vault", "retryAfter", loginRetryInterval)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(loginRetryInterval):
+			}
+			continue
+		}
+		m.setAuthenticated(secret.Auth.LeaseDuration)
+		m.Log.Info("Logged in to Vault", "ttl", secret.Auth.LeaseDuration, "renewable", secret.Auth.Renewable)
+
+		if err := m.watch(ctx, secret); err != nil {
+			m.setFailed(err)
+			m.Log.Error(err, "Vault token renewal failed, logging in again")
+		} else {
+			m.Log.Info("Vault token reached its max TTL, logging in again")
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every replica
+// keeps its token alive so readiness reflects Vault access on standbys too.
+func (m *TokenManager) NeedLeaderElection() bool {
+	return false
+}
+
+// Err returns nil while the manager holds a valid Vault token. Otherwise it
+// returns why it does not, wrapping the last *LoginError if login failed.
+func (m *TokenManager) Err() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.authenticated {
+		if m.lastErr != nil {
+			return fmt.Errorf("not authenticated to Vault: %w", m.lastErr)
+		}
+		return errors.New("not authenticated to Vault")
+	}
+	if !m.expiresAt.IsZero() && time.Now().After(m.expiresAt) {
+		return fmt.Errorf("vault token expired at %s", m.expiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Checker is a healthz.Checker that fails while the manager holds no valid
+// Vault token.
+func (m *TokenManager) Checker(_ *http.Request) error {
+	return m.Err()
+}
+
+// watch renews the token in secret until renewal stops or ctx is cancelled
+func (m *TokenManager) watch(ctx context.Context, secret *vault.Secret) error {
+	// Tokens without a TTL (e.g. root tokens) never expire
+	if secret.Auth.LeaseDuration == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	// Bail out on the first renewal error so we can log in again right away;
+	// non-renewable tokens are simply held until they are about to expire
+	behavior := vault.RenewBehaviorErrorOnErrors
+	if !secret.Auth.Renewable {
+		behavior = vault.RenewBehaviorRenewDisabled
+	}
+
+	watcher, err := m.Client.NewLifetimeWatcher(&vault.LifetimeWatcherInput{
+		Secret:        secret,
+		RenewBehavior: behavior,
+	})
+	if err != nil {
+		return err
+	}
+	go watcher.Start()
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watcher.DoneCh():
+			return err
+		case renewal := <-watcher.RenewCh():
+			m.setAuthenticated(renewal.Secret.Auth.LeaseDuration)
+			m.Log.V(1).Info("Renewed Vault token", "ttl", renewal.Secret.Auth.LeaseDuration)
+		}
+	}
+}
+
+func (m *TokenManager) setAuthenticated(ttlSeconds int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.authenticated = true
+	m.lastErr = nil
+	m.expiresAt = time.Time{}
+	if ttlSeconds > 0 {
+		m.expiresAt = time.Now().Add(time.Duration(ttlSeconds) * time.Second)
+	}
+}
+
+func (m *TokenManager) setFailed(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastErr = err
+	// A failed renewal leaves the old token usable until it expires
+	if !m.expiresAt.IsZero() && time.Now().After(m.expiresAt) {
+		m.authenticated = false
+	}
+}
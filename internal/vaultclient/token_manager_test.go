@@ -0,0 +1,207 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vaultclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	vault "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeVault serves the token and login endpoints used by the authenticators
+type fakeVault struct {
+	server  *httptest.Server
+	logins  atomic.Int32
+	unwraps atomic.Int32
+	// loginBody is the last request body sent to a login endpoint
+	loginBody map[string]interface{}
+	// ttl is the lease duration handed out for new tokens
+	ttl int
+}
+
+func newFakeVault() *fakeVault {
+	f := &fakeVault{ttl: 3600}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/kubernetes/login", func(w http.ResponseWriter, r *http.Request) {
+		f.logins.Add(1)
+		_ = json.NewDecoder(r.Body).Decode(&f.loginBody)
+		writeJSON(w, map[string]interface{}{
+			"auth": map[string]interface{}{
+				"client_token":   "s.k8s",
+				"renewable":      true,
+				"lease_duration": f.ttl,
+			},
+		})
+	})
+	mux.HandleFunc("/v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		f.logins.Add(1)
+		_ = json.NewDecoder(r.Body).Decode(&f.loginBody)
+		if f.loginBody["secret_id"] != "the-secret-id" {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]interface{}{"errors": []string{"invalid role or secret ID"}})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"auth": map[string]interface{}{
+				"client_token":   "s.approle",
+				"renewable":      true,
+				"lease_duration": f.ttl,
+			},
+		})
+	})
+	mux.HandleFunc("/v1/sys/wrapping/unwrap", func(w http.ResponseWriter, r *http.Request) {
+		f.unwraps.Add(1)
+		if r.Header.Get("X-Vault-Token") != "s.wrapping" {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]interface{}{"errors": []string{"wrapping token is not valid or does not exist"}})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"data": map[string]interface{}{"secret_id": "the-secret-id"},
+		})
+	})
+	mux.HandleFunc("/v1/auth/token/lookup-self", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.static" {
+			w.WriteHeader(http.StatusForbidden)
+			writeJSON(w, map[string]interface{}{"errors": []string{"permission denied"}})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"data": map[string]interface{}{
+				"id":        "s.static",
+				"ttl":       f.ttl,
+				"renewable": false,
+			},
+		})
+	})
+	f.server = httptest.NewServer(mux)
+	return f
+}
+
+func (f *fakeVault) client() *vault.Client {
+	cfg := vault.DefaultConfig()
+	cfg.Address = f.server.URL
+	client, err := vault.NewClient(cfg)
+	Expect(err).NotTo(HaveOccurred())
+	client.ClearToken()
+	return client
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+var _ = Describe("Vault authentication", func() {
+	var fake *fakeVault
+
+	BeforeEach(func() {
+		fake = newFakeVault()
+		DeferCleanup(fake.server.Close)
+	})
+
+	It("logs in with the Kubernetes auth method", func() {
+		tokenPath := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(tokenPath, []byte("sa-jwt\n"), 0o600)).To(Succeed())
+
+		client := fake.client()
+		auth := &KubernetesAuth{Role: "secret-rotator", TokenPath: tokenPath}
+		secret, err := auth.Login(context.Background(), client)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Auth.ClientToken).To(Equal("s.k8s"))
+		Expect(client.Token()).To(Equal("s.k8s"))
+		Expect(fake.loginBody).To(HaveKeyWithValue("role", "secret-rotator"))
+		Expect(fake.loginBody).To(HaveKeyWithValue("jwt", "sa-jwt"))
+	})
+
+	It("rejects an invalid static token", func() {
+		_, err := (&TokenAuth{Token: "s.bogus"}).Login(context.Background(), fake.client())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports readiness once the token manager has logged in", func() {
+		manager := &TokenManager{
+			Client: fake.client(),
+			Auth:   &TokenAuth{Token: "s.static"},
+			Log:    logr.Discard(),
+		}
+		Expect(manager.Checker(nil)).NotTo(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- manager.Start(ctx) }()
+
+		Eventually(func() error { return manager.Checker(nil) }).Should(Succeed())
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("stays unready while login fails", func() {
+		manager := &TokenManager{
+			Client: fake.client(),
+			Auth:   &TokenAuth{Token: "s.bogus"},
+			Log:    logr.Discard(),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = manager.Start(ctx) }()
+
+		Consistently(func() error { return manager.Checker(nil) }).ShouldNot(Succeed())
+	})
+})
+
+var _ = Describe("ClientCache", func() {
+	var fake *fakeVault
+
+	BeforeEach(func() {
+		fake = newFakeVault()
+		DeferCleanup(fake.server.Close)
+	})
+
+	It("reuses a client until the fingerprint changes", func() {
+		tokenPath := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(tokenPath, []byte("sa-jwt"), 0o600)).To(Succeed())
+		auth := &KubernetesAuth{Role: "team-a", TokenPath: tokenPath}
+		cache := &ClientCache{}
+		conn := Connection{Address: fake.server.URL, Namespace: "team-a"}
+
+		first, err := cache.Get(context.Background(), "ns/team-a", "1", conn, auth)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first.Token()).To(Equal("s.k8s"))
+		Expect(first.Namespace()).To(Equal("team-a"))
+
+		again, err := cache.Get(context.Background(), "ns/team-a", "1", conn, auth)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(BeIdenticalTo(first))
+		Expect(fake.logins.Load()).To(BeEquivalentTo(1))
+
+		rebuilt, err := cache.Get(context.Background(), "ns/team-a", "2", conn, auth)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rebuilt).NotTo(BeIdenticalTo(first))
+		Expect(fake.logins.Load()).To(BeEquivalentTo(2))
+	})
+})